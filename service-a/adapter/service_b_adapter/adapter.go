@@ -1,7 +1,12 @@
 package service_b_adapter
 
 import (
+	"context"
+	"sync"
+	"time"
+
 	"service-a/adapter/service_b_adapter/pb"
+	"service-a/util/config"
 
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel/trace"
@@ -15,12 +20,16 @@ type Adapter struct {
 	logger *logrus.Logger
 	tracer trace.Tracer
 
+	cc             *grpc.ClientConn
 	serviceBClient pb.BServiceClient
+
+	mu      sync.RWMutex
+	timeout time.Duration
 }
 
-// NewAdapter creates a new grpc adapter
+// NewAdapter creates a new grpc adapter for the service_b config
 func NewAdapter(
-	serviceName string,
+	config config.ServiceB,
 	logger *logrus.Logger,
 	tracer trace.Tracer,
 	cc *grpc.ClientConn,
@@ -28,11 +37,40 @@ func NewAdapter(
 	serviceBClient := pb.NewBServiceClient(cc)
 
 	return &Adapter{
-		serviceName: serviceName,
+		serviceName: config.Name,
 
 		logger: logger,
 		tracer: tracer,
 
+		cc:             cc,
 		serviceBClient: serviceBClient,
+
+		timeout: config.Timeout,
 	}
 }
+
+// SetConfig applies the live service_b settings to the following calls
+func (client *Adapter) SetConfig(config config.ServiceB) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	client.timeout = config.Timeout
+}
+
+// callContext bounds a unary call by service_b.timeout, when set
+func (client *Adapter) callContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	client.mu.RLock()
+	timeout := client.timeout
+	client.mu.RUnlock()
+
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// Close closes the underlying grpc connection
+func (client *Adapter) Close() error {
+	return client.cc.Close()
+}
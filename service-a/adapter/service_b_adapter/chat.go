@@ -0,0 +1,125 @@
+package service_b_adapter
+
+import (
+	"context"
+	"fmt"
+
+	"service-a/adapter/service_b_adapter/pb"
+	"service-a/util/logging"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// ChatStream is an open Chat call on service-b. Exchanges are sequential, a
+// stream must not be used by several goroutines at once.
+type ChatStream struct {
+	client *Adapter
+	stream pb.BService_ChatClient
+	cancel context.CancelFunc
+}
+
+// OpenChat opens a Chat call that lasts until Close is called or ctx is done
+func (client *Adapter) OpenChat(ctx context.Context) (*ChatStream, error) {
+	const op = "service_b_adapter.Adapter.OpenChat"
+
+	// Get logger with trace id
+	logger := logging.LogWithTrace(ctx, client.logger)
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	stream, err := client.serviceBClient.Chat(ctx)
+	if err != nil {
+		cancel()
+
+		logger.WithFields(logrus.Fields{
+			"[op]":  op,
+			"error": err.Error(),
+		}).Error()
+
+		return nil, fmt.Errorf("error opening chat stream: %w", err)
+	}
+
+	return &ChatStream{
+		client: client,
+		stream: stream,
+		cancel: cancel,
+	}, nil
+}
+
+// Exchange sends message and waits for its pong. The context of ctx's span
+// is sent along so that service-b continues the exchange's trace.
+func (chat *ChatStream) Exchange(ctx context.Context, message string, sequence int64) (*pb.ChatResponse, error) {
+	const op = "service_b_adapter.ChatStream.Exchange"
+
+	// Start span
+	ctx, span := chat.client.tracer.Start(ctx, op)
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("service_b_adapter.operation", "chat"),
+		attribute.String("service_b_adapter.input.message", message),
+		attribute.Int64("service_b_adapter.input.sequence", sequence),
+	)
+
+	// Get logger with trace id
+	logger := logging.LogWithTrace(ctx, chat.client.logger)
+
+	request := &pb.ChatRequest{
+		Message:      message,
+		Sequence:     sequence,
+		TraceContext: make(map[string]string),
+	}
+
+	otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(request.TraceContext))
+
+	logger.WithFields(logrus.Fields{
+		"[op]":    op,
+		"request": request,
+		"type":    fmt.Sprintf("%T", request),
+	}).Info()
+
+	err := chat.stream.Send(request)
+	if err != nil {
+		// The reason the stream broke is in its status, returned by Recv
+		if _, recvErr := chat.stream.Recv(); recvErr != nil {
+			err = recvErr
+		}
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, fmt.Errorf("error sending message: %w", err)
+	}
+
+	response, err := chat.stream.Recv()
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"[op]":    op,
+			"request": request,
+			"error":   err.Error(),
+		}).Error()
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, fmt.Errorf("error receiving message: %w", err)
+	}
+
+	logger.WithFields(logrus.Fields{
+		"[op]":     op,
+		"response": response,
+		"type":     fmt.Sprintf("%T", response),
+	}).Info()
+
+	return response, nil
+}
+
+// Close ends the call
+func (chat *ChatStream) Close() {
+	_ = chat.stream.CloseSend()
+	chat.cancel()
+}
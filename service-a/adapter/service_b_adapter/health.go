@@ -0,0 +1,23 @@
+package service_b_adapter
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Check asks service-b for its serving status through the standard gRPC
+// health service
+func (client *Adapter) Check(ctx context.Context) error {
+	response, err := grpc_health_v1.NewHealthClient(client.cc).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		return fmt.Errorf("health check failed (connection %s): %w", client.cc.GetState(), err)
+	}
+
+	if response.GetStatus() != grpc_health_v1.HealthCheckResponse_SERVING {
+		return fmt.Errorf("%s is %s", client.serviceName, response.GetStatus())
+	}
+
+	return nil
+}
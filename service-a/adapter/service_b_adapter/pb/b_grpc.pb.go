@@ -19,7 +19,9 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	BService_Ping_FullMethodName = "/pb.BService/Ping"
+	BService_Ping_FullMethodName       = "/pb.BService/Ping"
+	BService_PingStream_FullMethodName = "/pb.BService/PingStream"
+	BService_Chat_FullMethodName       = "/pb.BService/Chat"
 )
 
 // BServiceClient is the client API for BService service.
@@ -29,6 +31,10 @@ const (
 // B service definition
 type BServiceClient interface {
 	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error)
+	// Streams count pong messages, one every interval_ms milliseconds
+	PingStream(ctx context.Context, in *PingStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PingStreamResponse], error)
+	// Answers every chat message with a pong, for as long as the stream is open
+	Chat(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ChatRequest, ChatResponse], error)
 }
 
 type bServiceClient struct {
@@ -49,6 +55,38 @@ func (c *bServiceClient) Ping(ctx context.Context, in *PingRequest, opts ...grpc
 	return out, nil
 }
 
+func (c *bServiceClient) PingStream(ctx context.Context, in *PingStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PingStreamResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BService_ServiceDesc.Streams[0], BService_PingStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[PingStreamRequest, PingStreamResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BService_PingStreamClient = grpc.ServerStreamingClient[PingStreamResponse]
+
+func (c *bServiceClient) Chat(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ChatRequest, ChatResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BService_ServiceDesc.Streams[1], BService_Chat_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ChatRequest, ChatResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BService_ChatClient = grpc.BidiStreamingClient[ChatRequest, ChatResponse]
+
 // BServiceServer is the server API for BService service.
 // All implementations must embed UnimplementedBServiceServer
 // for forward compatibility.
@@ -56,6 +94,10 @@ func (c *bServiceClient) Ping(ctx context.Context, in *PingRequest, opts ...grpc
 // B service definition
 type BServiceServer interface {
 	Ping(context.Context, *PingRequest) (*PingResponse, error)
+	// Streams count pong messages, one every interval_ms milliseconds
+	PingStream(*PingStreamRequest, grpc.ServerStreamingServer[PingStreamResponse]) error
+	// Answers every chat message with a pong, for as long as the stream is open
+	Chat(grpc.BidiStreamingServer[ChatRequest, ChatResponse]) error
 	mustEmbedUnimplementedBServiceServer()
 }
 
@@ -69,6 +111,12 @@ type UnimplementedBServiceServer struct{}
 func (UnimplementedBServiceServer) Ping(context.Context, *PingRequest) (*PingResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
 }
+func (UnimplementedBServiceServer) PingStream(*PingStreamRequest, grpc.ServerStreamingServer[PingStreamResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method PingStream not implemented")
+}
+func (UnimplementedBServiceServer) Chat(grpc.BidiStreamingServer[ChatRequest, ChatResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Chat not implemented")
+}
 func (UnimplementedBServiceServer) mustEmbedUnimplementedBServiceServer() {}
 func (UnimplementedBServiceServer) testEmbeddedByValue()                  {}
 
@@ -108,6 +156,24 @@ func _BService_Ping_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BService_PingStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PingStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BServiceServer).PingStream(m, &grpc.GenericServerStream[PingStreamRequest, PingStreamResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BService_PingStreamServer = grpc.ServerStreamingServer[PingStreamResponse]
+
+func _BService_Chat_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(BServiceServer).Chat(&grpc.GenericServerStream[ChatRequest, ChatResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BService_ChatServer = grpc.BidiStreamingServer[ChatRequest, ChatResponse]
+
 // BService_ServiceDesc is the grpc.ServiceDesc for BService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -120,6 +186,18 @@ var BService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _BService_Ping_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "PingStream",
+			Handler:       _BService_PingStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Chat",
+			Handler:       _BService_Chat_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
 	Metadata: "b.proto",
 }
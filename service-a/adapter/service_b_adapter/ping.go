@@ -37,7 +37,10 @@ func (client *Adapter) Ping(ctx context.Context, message string) (*pb.PingRespon
 	}).Info()
 
 	// Call service B
-	response, err := client.serviceBClient.Ping(ctx, request)
+	callCtx, cancel := client.callContext(ctx)
+	defer cancel()
+
+	response, err := client.serviceBClient.Ping(callCtx, request)
 	if err != nil {
 		logger.WithFields(logrus.Fields{
 			"[op]":    op,
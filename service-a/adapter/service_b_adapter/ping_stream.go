@@ -0,0 +1,104 @@
+package service_b_adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"service-a/adapter/service_b_adapter/pb"
+	"service-a/util/logging"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// PingStream opens a PingStream call and hands every received message to
+// emit. Cancelling ctx cancels the call on service-b.
+func (client *Adapter) PingStream(ctx context.Context, message string, count int, interval time.Duration, emit func(*pb.PingStreamResponse) error) error {
+	const op = "service_b_adapter.Adapter.PingStream"
+
+	// Start span
+	ctx, span := client.tracer.Start(ctx, op)
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("service_b_adapter.operation", "ping_stream"),
+		attribute.String("service_b_adapter.input.message", message),
+		attribute.Int("service_b_adapter.input.count", count),
+		attribute.Int64("service_b_adapter.input.interval_ms", interval.Milliseconds()),
+	)
+
+	// Get logger with trace id
+	logger := logging.LogWithTrace(ctx, client.logger)
+
+	// The call ends with this function, whether the stream completed or not
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	request := &pb.PingStreamRequest{
+		PingMessage: message,
+		Count:       int32(count),
+		IntervalMs:  int32(interval.Milliseconds()),
+	}
+
+	logger.WithFields(logrus.Fields{
+		"[op]":    op,
+		"request": request,
+		"type":    fmt.Sprintf("%T", request),
+	}).Info()
+
+	// Call service B
+	stream, err := client.serviceBClient.PingStream(ctx, request)
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"[op]":    op,
+			"request": request,
+			"type":    fmt.Sprintf("%T", request),
+			"error":   err.Error(),
+		}).Error()
+
+		return fmt.Errorf("error sending request: %w", err)
+	}
+
+	received := 0
+	for {
+		response, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			logger.WithFields(logrus.Fields{
+				"[op]":     op,
+				"request":  request,
+				"received": received,
+				"error":    err.Error(),
+			}).Error()
+
+			return fmt.Errorf("error receiving message: %w", err)
+		}
+
+		received++
+		span.AddEvent("message.received", trace.WithAttributes(
+			attribute.Int("service_b_adapter.stream.sequence", int(response.GetSequence())),
+		))
+
+		err = emit(response)
+		if err != nil {
+			return err
+		}
+	}
+
+	logger.WithFields(logrus.Fields{
+		"[op]":     op,
+		"received": received,
+	}).Info()
+
+	span.SetAttributes(
+		attribute.Int("service_b_adapter.output.received", received),
+	)
+
+	return nil
+}
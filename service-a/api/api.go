@@ -3,39 +3,139 @@ package api
 import (
 	"service-a/middleware"
 	"service-a/service"
+	"service-a/util/auth"
+	"service-a/util/config"
+	"service-a/util/health"
 
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/swagger"
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel/trace"
 )
 
 type Api struct {
+	config config.Config
+
 	logger *logrus.Logger
 	tracer trace.Tracer
 
-	service *service.Service
+	service       *service.Service
+	checker       *health.Checker
+	deadlines     *middleware.Deadlines
+	rateLimiter   *middleware.RateLimiter
+	idempotency   *middleware.Idempotency
+	responseCache *middleware.ResponseCache
+	versions      *middleware.Versions
+	authenticator *auth.Authenticator
+	document      *openapi3.T
+	validator     *middleware.RequestValidator
 }
 
 func NewApi(
+	config config.Config,
 	logger *logrus.Logger,
 	tracer trace.Tracer,
 	service *service.Service,
+	checker *health.Checker,
+	deadlines *middleware.Deadlines,
+	rateLimiter *middleware.RateLimiter,
+	idempotency *middleware.Idempotency,
+	responseCache *middleware.ResponseCache,
+	versions *middleware.Versions,
+	authenticator *auth.Authenticator,
+	document *openapi3.T,
+	validator *middleware.RequestValidator,
 ) *Api {
 	return &Api{
+		config: config,
+
 		logger: logger,
 		tracer: tracer,
 
-		service: service,
+		service:       service,
+		checker:       checker,
+		deadlines:     deadlines,
+		rateLimiter:   rateLimiter,
+		idempotency:   idempotency,
+		responseCache: responseCache,
+		versions:      versions,
+		authenticator: authenticator,
+		document:      document,
+		validator:     validator,
 	}
 }
 
 func (api *Api) SetupRoutes(app *fiber.App) *fiber.App {
+	// Recover middleware, first so that it guards the whole chain
+	app.Use(middleware.Recover(api.logger))
+
+	// Probes registered ahead of the middlewares are neither traced nor logged
+	if !api.config.Health.Traced {
+		api.setupHealthRoutes(app)
+	}
+
+	// Tracing middleware
+	app.Use(middleware.Tracing(api.tracer))
+
+	// Trace response middleware
+	app.Use(middleware.TraceResponse(api.config.TraceResponse))
+
+	// Access log middleware
+	app.Use(middleware.AccessLog(api.logger))
+
 	// Error handler middleware
 	app.Use(middleware.ErrorHandler())
 
-	// Ping Routes
-	ping := app.Group("/ping")
-	ping.Get("/", api.Ping)
+	if api.config.Health.Traced {
+		api.setupHealthRoutes(app)
+	}
+
+	// Docs never require credentials
+	if api.config.OpenAPI.Enabled {
+		app.Get("/openapi.json", api.OpenAPI)
+		app.Get(docsPath+"/*", swagger.New(swagger.Config{
+			URL: "/openapi.json",
+		}))
+	}
+
+	// Authentication middleware, probes never require credentials
+	if api.config.Auth.Enabled {
+		app.Use(middleware.Auth(api.authenticator, api.logger))
+	}
+
+	// Request validation middleware, against the OpenAPI document
+	app.Use(api.validator.Handler())
+
+	// Versioned Routes
+	v1 := app.Group("/"+apiV1, api.versions.Handler(apiV1))
+	api.setupPingRoutes(v1.Group("/ping"))
+
+	v2 := app.Group("/"+apiV2, api.versions.Handler(apiV2))
+	api.setupPingRoutes(v2.Group("/ping"))
+
+	// Unversioned Routes, deprecated aliases of v1
+	api.setupPingRoutes(app.Group("/ping", api.versions.Alias(apiV1)))
+
+	api.checkDocument(app)
 
 	return app
 }
+
+func (api *Api) setupPingRoutes(ping fiber.Router) {
+	// Ping Routes
+	ping.Get("/", api.rateLimiter.Handler(), api.responseCache.Handler(), api.deadlines.Handler(), api.Ping)
+	ping.Post("/", api.rateLimiter.Handler(), api.deadlines.Handler(), api.idempotency.Handler(), api.PostPing)
+	ping.Post("/batch", api.rateLimiter.Handler(), api.deadlines.Handler(), api.idempotency.Handler(), api.PingBatch)
+
+	// Streams outlive their handler, they end with the client or service-b
+	ping.Get("/stream", api.rateLimiter.Handler(), api.PingStream)
+	ping.Get("/chat", api.rateLimiter.Handler(), api.ChatUpgrade, websocket.New(api.Chat))
+}
+
+func (api *Api) setupHealthRoutes(app *fiber.App) {
+	// Health Routes
+	app.Get("/healthz", api.Healthz)
+	app.Get("/readyz", api.Readyz)
+}
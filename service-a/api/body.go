@@ -0,0 +1,30 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// parseBody strictly decodes the JSON request body into out, unknown fields
+// and trailing data are rejected
+func parseBody(c *fiber.Ctx, out any) error {
+	if !c.Is("json") {
+		return fiber.NewError(fiber.StatusUnsupportedMediaType, "request body must be application/json")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(c.Body()))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(out); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body: %s", err))
+	}
+
+	if decoder.More() {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body: unexpected data after the JSON value")
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package api
+
+import (
+	"context"
+
+	"service-a/middleware"
+	"service-a/service"
+	"service-a/util/logging"
+	"service-a/util/tracing"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	chatContextLocal = "chat_context"
+	chatProblemLocal = "chat_problem"
+)
+
+type chatReply struct {
+	Sequence    int64               `json:"sequence"`
+	PongMessage string              `json:"pong_message,omitempty"`
+	TraceID     string              `json:"trace_id,omitempty"`
+	Error       *middleware.Problem `json:"error,omitempty"`
+}
+
+// ChatUpgrade hands the request context over to the WebSocket handler, which
+// runs once the request handlers returned
+func (api *Api) ChatUpgrade(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return fiber.ErrUpgradeRequired
+	}
+
+	c.Locals(chatContextLocal, c.UserContext())
+	c.Locals(chatProblemLocal, middleware.NewProblem(c, fiber.StatusInternalServerError, ""))
+
+	return c.Next()
+}
+
+// Chat relays every text message of the WebSocket to service-b's Chat stream
+// and answers it with a JSON reply holding the pong or a problem.
+//
+// The session span lasts as long as the connection. Every exchange starts a
+// new trace linked to the session span, so a long session does not turn into
+// a single giant trace.
+func (api *Api) Chat(conn *websocket.Conn) {
+	const op = "api.Api.Chat"
+
+	parent, ok := conn.Locals(chatContextLocal).(context.Context)
+	if !ok {
+		parent = context.Background()
+	}
+
+	problem, _ := conn.Locals(chatProblemLocal).(middleware.Problem)
+
+	// Start span
+	ctx, span := api.tracer.Start(parent, op)
+	defer span.End()
+
+	// The session runs outside the handler chain, a panic only closes it
+	defer func() {
+		if value := recover(); value != nil {
+			tracing.RecordPanic(ctx, api.logger, value)
+
+			_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "internal error"))
+		}
+	}()
+
+	span.SetAttributes(
+		attribute.String("api.endpoint", "/ping/chat"),
+		attribute.String("api.method", "WEBSOCKET"),
+	)
+
+	// Get logger with trace id
+	logger := logging.LogWithTrace(ctx, api.logger)
+
+	logger.WithFields(logrus.Fields{
+		"[op]": op,
+		"peer": conn.IP(),
+	}).Info("chat session opened")
+
+	// The call to service-b ends with the connection
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	session := api.service.OpenChat(ctx)
+	defer session.Close()
+
+	var sequence int64
+	for {
+		_, payload, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+
+			break
+		}
+
+		sequence++
+		reply := api.chatExchange(ctx, session, problem, string(payload), sequence)
+
+		err = conn.WriteJSON(reply)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+
+			break
+		}
+	}
+
+	logger.WithFields(logrus.Fields{
+		"[op]":      op,
+		"exchanged": sequence,
+	}).Info("chat session closed")
+
+	span.SetAttributes(
+		attribute.Int64("api.output.exchanged", sequence),
+	)
+}
+
+// chatExchange relays a single message in its own trace, linked to the session
+func (api *Api) chatExchange(sessionCtx context.Context, session *service.ChatSession, problem middleware.Problem, message string, sequence int64) chatReply {
+	const op = "api.Api.Chat.message"
+
+	// Start span
+	ctx, span := api.tracer.Start(sessionCtx, op,
+		trace.WithNewRoot(),
+		trace.WithLinks(trace.LinkFromContext(sessionCtx, attribute.String("link.type", "chat.session"))),
+	)
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int64("api.chat.sequence", sequence),
+		attribute.String("api.input.message", message),
+	)
+
+	reply := chatReply{
+		Sequence: sequence,
+		TraceID:  span.SpanContext().TraceID().String(),
+	}
+
+	params := &service.ChatParams{
+		Message:  message,
+		Sequence: sequence,
+	}
+
+	err := params.Validate()
+	if err != nil {
+		err = fiber.NewError(fiber.StatusBadRequest, err.Error())
+	} else {
+		var result *service.ChatResult
+
+		result, err = session.Exchange(ctx, params)
+		if err == nil {
+			reply.PongMessage = result.PongMessage
+		}
+	}
+
+	if err != nil {
+		// Record the error in the span
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		// The problem belongs to the exchange's trace, not the upgrade's
+		problem = problem.WithError(err)
+		problem.TraceID = reply.TraceID
+		problem.TraceURL = ""
+		reply.Error = &problem
+
+		return reply
+	}
+
+	span.SetStatus(codes.Ok, "message exchanged successfully")
+
+	return reply
+}
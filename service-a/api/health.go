@@ -0,0 +1,31 @@
+package api
+
+import (
+	"service-a/util/health"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type healthStatus struct {
+	Status string `json:"status"`
+}
+
+// Healthz reports that the process is alive
+func (api *Api) Healthz(c *fiber.Ctx) error {
+	return c.JSON(healthStatus{
+		Status: health.StatusOK,
+	})
+}
+
+// Readyz reports whether the service can handle traffic, with the result of
+// every readiness check
+func (api *Api) Readyz(c *fiber.Ctx) error {
+	report := api.checker.Run(c.UserContext())
+
+	status := fiber.StatusOK
+	if report.Status != health.StatusOK {
+		status = fiber.StatusServiceUnavailable
+	}
+
+	return c.Status(status).JSON(report)
+}
@@ -0,0 +1,426 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"service-a/middleware"
+	"service-a/service"
+	"service-a/util/config"
+	"service-a/util/health"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3gen"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	documentVersion = "1.0.0"
+
+	// docsPath serves the Swagger UI, it is not part of the document
+	docsPath = "/docs"
+)
+
+// NewDocument builds the OpenAPI 3.1 document of the routes set up by
+// SetupRoutes. Schemas are generated from the request and response types;
+// limits come from the config so that the document states what is enforced.
+func NewDocument(config config.Config) (*openapi3.T, error) {
+	schemas, err := generateSchemas()
+	if err != nil {
+		return nil, err
+	}
+
+	// Request bodies are decoded strictly, see parseBody
+	pingParams := schemas["PingParams"].Value
+	pingParams.AdditionalProperties = openapi3.AdditionalProperties{Has: openapi3.Ptr(false)}
+	pingParams.Properties["ping_message"] = openapi3.NewSchemaRef("", openapi3.NewStringSchema().WithMinLength(1))
+
+	pingBatchParams := schemas["PingBatchParams"].Value
+	pingBatchParams.AdditionalProperties = openapi3.AdditionalProperties{Has: openapi3.Ptr(false)}
+	pingBatchParams.Properties["messages"] = openapi3.NewSchemaRef("", openapi3.NewArraySchema().
+		WithItems(openapi3.NewStringSchema().WithMinLength(1)).
+		WithMinItems(1).
+		WithMaxItems(int64(config.Batch.MaxItems)))
+
+	document := &openapi3.T{
+		OpenAPI: "3.1.0",
+		Info: &openapi3.Info{
+			Title:       config.App.Name,
+			Description: "Front end of the OpenTelemetry demo, every ping is answered by service-b over gRPC.",
+			Version:     documentVersion,
+		},
+		Components: &openapi3.Components{
+			Schemas:         schemas,
+			Parameters:      documentParameters(),
+			Responses:       documentResponses(),
+			SecuritySchemes: documentSecuritySchemes(),
+		},
+		Paths: openapi3.NewPaths(
+			openapi3.WithPath("/healthz", &openapi3.PathItem{
+				Get: publicOperation(&openapi3.Operation{
+					OperationID: "Healthz",
+					Tags:        []string{"health"},
+					Summary:     "Liveness probe",
+					Responses: openapi3.NewResponses(
+						openapi3.WithStatus(http.StatusOK, &openapi3.ResponseRef{Value: jsonResponse("The process serves requests", "HealthStatus")}),
+					),
+				}),
+			}),
+			openapi3.WithPath("/readyz", &openapi3.PathItem{
+				Get: publicOperation(&openapi3.Operation{
+					OperationID: "Readyz",
+					Tags:        []string{"health"},
+					Summary:     "Readiness probe",
+					Description: "Runs the readiness checks: config, service-b and telemetry.",
+					Responses: openapi3.NewResponses(
+						openapi3.WithStatus(http.StatusOK, &openapi3.ResponseRef{Value: jsonResponse("Every check passed", "HealthReport")}),
+						openapi3.WithStatus(http.StatusServiceUnavailable, &openapi3.ResponseRef{Value: jsonResponse("A check failed", "HealthReport")}),
+					),
+				}),
+			}),
+			openapi3.WithPath("/openapi.json", &openapi3.PathItem{
+				Get: publicOperation(&openapi3.Operation{
+					OperationID: "OpenAPI",
+					Tags:        []string{"docs"},
+					Summary:     "This document",
+					Responses: openapi3.NewResponses(
+						openapi3.WithStatus(http.StatusOK, &openapi3.ResponseRef{Value: openapi3.NewResponse().
+							WithDescription("The OpenAPI document").
+							WithJSONSchema(openapi3.NewObjectSchema())}),
+					),
+				}),
+			}),
+		),
+	}
+
+	// Unversioned routes are deprecated aliases of v1
+	for _, version := range []string{"", apiV1, apiV2} {
+		for path, item := range pingPaths(config, version) {
+			document.Paths.Set(versionPrefix(version)+path, item)
+		}
+	}
+
+	// Probes and docs override the default security with an empty one
+	if config.Auth.Enabled {
+		document.Security = openapi3.SecurityRequirements{
+			openapi3.NewSecurityRequirement().Authenticate("apiKey"),
+			openapi3.NewSecurityRequirement().Authenticate("bearer"),
+		}
+
+		for _, item := range document.Paths.Map() {
+			for _, operation := range item.Operations() {
+				if operation.Security == nil {
+					operation.Responses.Set(fmt.Sprint(http.StatusUnauthorized), responseRef("Unauthorized"))
+				}
+			}
+		}
+	}
+
+	err = openapi3.NewLoader().ResolveRefsIn(document, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve openapi document refs: %w", err)
+	}
+
+	err = document.Validate(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("invalid openapi document: %w", err)
+	}
+
+	return document, nil
+}
+
+// pingPaths describes the ping routes of an API version, "" for the
+// unversioned aliases
+func pingPaths(config config.Config, version string) map[string]*openapi3.PathItem {
+	tags := []string{"ping"}
+	if version != "" {
+		tags = []string{"ping " + version}
+	}
+
+	pingSchema := "PingResult"
+	if version == apiV2 {
+		pingSchema = "PingResponseV2"
+	}
+
+	return map[string]*openapi3.PathItem{
+		"/ping": {
+			Get: &openapi3.Operation{
+				OperationID: "Ping" + strings.ToUpper(version),
+				Deprecated:  version == "",
+				Tags:        tags,
+				Summary:     "Ping service-b",
+				Parameters: openapi3.Parameters{
+					&openapi3.ParameterRef{Value: openapi3.NewQueryParameter("message").
+						WithDescription("Message sent to service-b, \"error\" makes service-b fail").
+						WithSchema(openapi3.NewStringSchema())},
+					parameterRef("RequestTimeout"),
+					&openapi3.ParameterRef{Value: openapi3.NewHeaderParameter(fiber.HeaderIfNoneMatch).
+						WithDescription("ETag of a cached response, answered with 304 while it is still current").
+						WithSchema(openapi3.NewStringSchema())},
+				},
+				Responses: pingResponses(http.StatusOK, jsonResponse("Pong message of service-b, from the response cache when enabled", pingSchema), "BadRequest", "NotModified", "TooManyRequests", "GatewayTimeout"),
+			},
+			Post: &openapi3.Operation{
+				OperationID: "PostPing" + strings.ToUpper(version),
+				Deprecated:  version == "",
+				Tags:        tags,
+				Summary:     "Ping service-b with a JSON body",
+				Parameters:  openapi3.Parameters{parameterRef("RequestTimeout"), parameterRef("IdempotencyKey")},
+				RequestBody: jsonRequestBody("PingParams"),
+				Responses:   pingResponses(http.StatusOK, jsonResponse("Pong message of service-b", pingSchema), "BadRequest", "Conflict", "UnsupportedMediaType", "UnprocessableEntity", "TooManyRequests", "GatewayTimeout"),
+			},
+		},
+		"/ping/batch": {
+			Post: &openapi3.Operation{
+				OperationID: "PingBatch" + strings.ToUpper(version),
+				Deprecated:  version == "",
+				Tags:        tags,
+				Summary:     "Ping service-b once per message",
+				Description: fmt.Sprintf("Messages are sent in parallel, %d at a time. A failed message is reported in its result, not as an error of the whole batch.", config.Batch.Concurrency),
+				Parameters:  openapi3.Parameters{parameterRef("RequestTimeout"), parameterRef("IdempotencyKey")},
+				RequestBody: jsonRequestBody("PingBatchParams"),
+				Responses:   pingResponses(http.StatusOK, jsonResponse("Result of every message, in request order", "PingBatchResponse"), "BadRequest", "Conflict", "UnsupportedMediaType", "UnprocessableEntity", "TooManyRequests", "GatewayTimeout"),
+			},
+		},
+		"/ping/stream": {
+			Get: &openapi3.Operation{
+				OperationID: "PingStream" + strings.ToUpper(version),
+				Deprecated:  version == "",
+				Tags:        tags,
+				Summary:     "Stream pong messages as Server-Sent Events",
+				Description: "Sends a \"pong\" event per message of service-b, an \"error\" event holding problem details if the stream fails, and a final \"done\" event.",
+				Parameters: openapi3.Parameters{
+					&openapi3.ParameterRef{Value: openapi3.NewQueryParameter("message").
+						WithSchema(openapi3.NewStringSchema())},
+					&openapi3.ParameterRef{Value: openapi3.NewQueryParameter("count").
+						WithDescription("Number of pong messages").
+						WithSchema(openapi3.NewIntegerSchema().WithMin(1).WithMax(service.MaxStreamCount).WithDefault(5))},
+					&openapi3.ParameterRef{Value: openapi3.NewQueryParameter("interval_ms").
+						WithDescription("Delay between pong messages").
+						WithSchema(openapi3.NewIntegerSchema().WithMin(0).WithMax(float64(service.MaxStreamInterval.Milliseconds())).WithDefault(1000))},
+				},
+				Responses: pingResponses(http.StatusOK, &openapi3.Response{
+					Description: openapi3.Ptr("Stream of events"),
+					Content:     openapi3.NewContentWithSchema(openapi3.NewStringSchema(), []string{"text/event-stream"}),
+				}, "BadRequest", "TooManyRequests"),
+			},
+		},
+		"/ping/chat": {
+			Get: &openapi3.Operation{
+				OperationID: "Chat" + strings.ToUpper(version),
+				Deprecated:  version == "",
+				Tags:        tags,
+				Summary:     "Chat with service-b over a WebSocket",
+				Description: "Every text message is answered with a ChatReply JSON message, service-b failures included.",
+				Responses: pingResponses(http.StatusSwitchingProtocols, &openapi3.Response{
+					Description: openapi3.Ptr("Switched to the WebSocket protocol"),
+				}, "UpgradeRequired", "TooManyRequests"),
+			},
+		},
+	}
+}
+
+// OpenAPI serves the OpenAPI document
+func (api *Api) OpenAPI(c *fiber.Ctx) error {
+	return c.JSON(api.document)
+}
+
+// checkDocument warns about the routes missing from the OpenAPI document
+func (api *Api) checkDocument(app *fiber.App) {
+	const op = "api.Api.checkDocument"
+
+	for _, route := range app.GetRoutes(true) {
+		if route.Method == fiber.MethodHead || strings.HasPrefix(route.Path, docsPath) {
+			continue
+		}
+
+		path := strings.TrimSuffix(route.Path, "/")
+		item := api.document.Paths.Value(path)
+		if item == nil || item.GetOperation(route.Method) == nil {
+			api.logger.WithFields(logrus.Fields{
+				"[op]":   op,
+				"method": route.Method,
+				"path":   path,
+			}).Warn("route is missing from the openapi document")
+		}
+	}
+}
+
+// generateSchemas generates the component schemas of the request and
+// response types
+func generateSchemas() (openapi3.Schemas, error) {
+	schemas := openapi3.Schemas{}
+	generator := openapi3gen.NewGenerator(openapi3gen.SchemaCustomizer(customizeSchema))
+
+	types := map[string]any{
+		"PingParams":        service.PingParams{},
+		"PingResult":        service.PingResult{},
+		"PingResponseV2":    pingResponseV2{},
+		"PingBatchParams":   service.PingBatchParams{},
+		"PingBatchResponse": pingBatchResponse{},
+		"ChatReply":         chatReply{},
+		"HealthStatus":      healthStatus{},
+		"HealthReport":      health.Report{},
+		"Problem":           middleware.Problem{},
+	}
+
+	for name, value := range types {
+		ref, err := generator.NewSchemaRefForValue(value, schemas)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate %s schema: %w", name, err)
+		}
+
+		schemas[name] = ref
+	}
+
+	return schemas, nil
+}
+
+// customizeSchema drops "nullable", which OpenAPI 3.1 no longer has, and
+// marks the struct fields without omitempty as required
+func customizeSchema(_ string, t reflect.Type, _ reflect.StructTag, schema *openapi3.Schema) error {
+	schema.Nullable = false
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || strings.Contains(options, "omitempty") {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		schema.Required = append(schema.Required, name)
+	}
+
+	return nil
+}
+
+func documentParameters() openapi3.ParametersMap {
+	return openapi3.ParametersMap{
+		"IdempotencyKey": &openapi3.ParameterRef{Value: openapi3.NewHeaderParameter(middleware.HeaderIdempotencyKey).
+			WithDescription("Unique key of the request: a retry with the same key and body gets the first response again, with an Idempotent-Replayed header").
+			WithSchema(openapi3.NewStringSchema().WithMinLength(1).WithMaxLength(255))},
+		"RequestTimeout": &openapi3.ParameterRef{Value: openapi3.NewHeaderParameter(middleware.HeaderRequestTimeout).
+			WithDescription("Deadline of the request in milliseconds or as a duration like \"1.5s\", capped by timeouts.max").
+			WithSchema(openapi3.NewStringSchema())},
+	}
+}
+
+func documentResponses() openapi3.ResponseBodies {
+	problems := map[string]string{
+		"BadRequest":           "Invalid request",
+		"Unauthorized":         "Missing or invalid credentials",
+		"UnsupportedMediaType": "Request body is not JSON",
+		"UpgradeRequired":      "Not a WebSocket upgrade request",
+		"Conflict":             "A request with the same Idempotency-Key is still in progress",
+		"UnprocessableEntity":  "The Idempotency-Key was used for a different request",
+		"TooManyRequests":      "Rate limit exceeded, retry after the Retry-After header",
+		"GatewayTimeout":       "Deadline of the request exceeded",
+		"Problem":              "Any other failure, service-b errors included",
+	}
+
+	responses := openapi3.ResponseBodies{}
+	for name, description := range problems {
+		responses[name] = &openapi3.ResponseRef{Value: &openapi3.Response{
+			Description: openapi3.Ptr(description),
+			Content:     openapi3.NewContentWithSchemaRef(schemaRef("Problem"), []string{middleware.MIMEProblemJSON}),
+		}}
+	}
+
+	// A 304 has no body
+	responses["NotModified"] = &openapi3.ResponseRef{Value: openapi3.NewResponse().
+		WithDescription("The response named by If-None-Match is still current")}
+
+	return responses
+}
+
+func documentSecuritySchemes() openapi3.SecuritySchemes {
+	return openapi3.SecuritySchemes{
+		"apiKey": &openapi3.SecuritySchemeRef{Value: openapi3.NewSecurityScheme().
+			WithType("apiKey").
+			WithIn("header").
+			WithName(middleware.HeaderAPIKey)},
+		"bearer": &openapi3.SecuritySchemeRef{Value: openapi3.NewJWTSecurityScheme()},
+	}
+}
+
+// pingResponses holds the success response of a ping route, the problems it
+// may answer with and the default problem response
+func pingResponses(status int, success *openapi3.Response, problems ...string) *openapi3.Responses {
+	responses := openapi3.NewResponses(
+		openapi3.WithStatus(status, &openapi3.ResponseRef{Value: success}),
+	)
+
+	for _, name := range problems {
+		responses.Set(fmt.Sprint(problemStatuses[name]), responseRef(name))
+	}
+
+	responses.Set("default", responseRef("Problem"))
+
+	return responses
+}
+
+var problemStatuses = map[string]int{
+	"BadRequest":           http.StatusBadRequest,
+	"UnsupportedMediaType": http.StatusUnsupportedMediaType,
+	"UpgradeRequired":      http.StatusUpgradeRequired,
+	"Conflict":             http.StatusConflict,
+	"NotModified":          http.StatusNotModified,
+	"UnprocessableEntity":  http.StatusUnprocessableEntity,
+	"TooManyRequests":      http.StatusTooManyRequests,
+	"GatewayTimeout":       http.StatusGatewayTimeout,
+}
+
+// publicOperation overrides the default security, the operation never
+// requires credentials
+func publicOperation(operation *openapi3.Operation) *openapi3.Operation {
+	operation.Security = &openapi3.SecurityRequirements{}
+
+	return operation
+}
+
+func jsonResponse(description, schema string) *openapi3.Response {
+	return &openapi3.Response{
+		Description: openapi3.Ptr(description),
+		Content:     openapi3.NewContentWithJSONSchemaRef(schemaRef(schema)),
+	}
+}
+
+func jsonRequestBody(schema string) *openapi3.RequestBodyRef {
+	return &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().
+		WithRequired(true).
+		WithContent(openapi3.NewContentWithJSONSchemaRef(schemaRef(schema)))}
+}
+
+// schemaRef, parameterRef and responseRef point to the components, their
+// value is resolved once the document is built
+func schemaRef(name string) *openapi3.SchemaRef {
+	return &openapi3.SchemaRef{Ref: "#/components/schemas/" + name}
+}
+
+func parameterRef(name string) *openapi3.ParameterRef {
+	return &openapi3.ParameterRef{Ref: "#/components/parameters/" + name}
+}
+
+func responseRef(name string) *openapi3.ResponseRef {
+	return &openapi3.ResponseRef{Ref: "#/components/responses/" + name}
+}
@@ -6,6 +6,7 @@ import (
 
 	"service-a/service"
 	"service-a/util/logging"
+	"service-a/util/wait"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
@@ -17,7 +18,7 @@ func (api *Api) Ping(c *fiber.Ctx) error {
 	const op = "api.Api.Ping"
 
 	// Start span
-	ctx, span := api.tracer.Start(c.Context(), op)
+	ctx, span := api.tracer.Start(c.UserContext(), op)
 	defer span.End()
 
 	span.SetAttributes(
@@ -31,7 +32,14 @@ func (api *Api) Ping(c *fiber.Ctx) error {
 	message := c.Query("message")
 
 	// Simulate a validation operation
-	time.Sleep(250 * time.Millisecond)
+	err := wait.Sleep(ctx, 250*time.Millisecond)
+	if err != nil {
+		// Record the error in the span
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return err
+	}
 
 	params := &service.PingParams{
 		PingMessage: message,
@@ -48,9 +56,8 @@ func (api *Api) Ping(c *fiber.Ctx) error {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		// The error handler translates the error to a problem response
+		return err
 	}
 
 	// Record success attributes
@@ -59,5 +66,5 @@ func (api *Api) Ping(c *fiber.Ctx) error {
 	)
 	span.SetStatus(codes.Ok, "request completed successfully")
 
-	return c.JSON(result)
+	return sendPingResult(c, result)
 }
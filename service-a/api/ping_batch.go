@@ -0,0 +1,103 @@
+package api
+
+import (
+	"service-a/middleware"
+	"service-a/service"
+	"service-a/util/logging"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type pingBatchItemResponse struct {
+	Index       int                 `json:"index"`
+	PongMessage string              `json:"pong_message,omitempty"`
+	Error       *middleware.Problem `json:"error,omitempty"`
+}
+
+type pingBatchResponse struct {
+	Results   []pingBatchItemResponse `json:"results"`
+	Succeeded int                     `json:"succeeded"`
+	Failed    int                     `json:"failed"`
+}
+
+// PingBatch pings service-b once per message of the body. The response holds
+// the result or the error of every message, in the order they were sent.
+func (api *Api) PingBatch(c *fiber.Ctx) error {
+	const op = "api.Api.PingBatch"
+
+	// Start span
+	ctx, span := api.tracer.Start(c.UserContext(), op)
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("api.endpoint", "/ping/batch"),
+		attribute.String("api.method", "POST"),
+	)
+
+	// Get logger with trace id
+	logger := logging.LogWithTrace(ctx, api.logger)
+
+	params := &service.PingBatchParams{}
+
+	err := parseBody(c, params)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return err
+	}
+
+	err = params.Validate(api.config.Batch.MaxItems)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	logger.WithFields(logrus.Fields{
+		"[op]": op,
+		"size": len(params.Messages),
+	}).Info()
+
+	result, err := api.service.PingBatch(ctx, params)
+	if err != nil {
+		// Record the error in the span
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		// The error handler translates the error to a problem response
+		return err
+	}
+
+	response := pingBatchResponse{
+		Results:   make([]pingBatchItemResponse, len(result.Items)),
+		Succeeded: result.Succeeded,
+		Failed:    result.Failed,
+	}
+
+	for i, item := range result.Items {
+		response.Results[i] = pingBatchItemResponse{Index: item.Index}
+
+		if item.Err != nil {
+			problem := middleware.ProblemFromError(c, item.Err)
+			response.Results[i].Error = &problem
+
+			continue
+		}
+
+		response.Results[i].PongMessage = item.Result.PongMessage
+	}
+
+	// Record success attributes
+	span.SetAttributes(
+		attribute.Int("api.output.succeeded", result.Succeeded),
+		attribute.Int("api.output.failed", result.Failed),
+	)
+	span.SetStatus(codes.Ok, "request completed successfully")
+
+	return c.JSON(response)
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"fmt"
+
+	"service-a/service"
+	"service-a/util/logging"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// PostPing is the JSON body variant of Ping
+func (api *Api) PostPing(c *fiber.Ctx) error {
+	const op = "api.Api.PostPing"
+
+	// Start span
+	ctx, span := api.tracer.Start(c.UserContext(), op)
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("api.endpoint", "/ping"),
+		attribute.String("api.method", "POST"),
+	)
+
+	// Get logger with trace id
+	logger := logging.LogWithTrace(ctx, api.logger)
+
+	params := &service.PingParams{}
+
+	err := parseBody(c, params)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return err
+	}
+
+	err = params.Validate()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	logger.WithFields(logrus.Fields{
+		"[op]":   op,
+		"params": fmt.Sprintf("%+v", params),
+	}).Info()
+
+	result, err := api.service.Ping(ctx, params)
+	if err != nil {
+		// Record the error in the span
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		// The error handler translates the error to a problem response
+		return err
+	}
+
+	// Record success attributes
+	span.SetAttributes(
+		attribute.String("api.output.result", fmt.Sprintf("%+v", result)),
+	)
+	span.SetStatus(codes.Ok, "request completed successfully")
+
+	return sendPingResult(c, result)
+}
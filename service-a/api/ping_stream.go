@@ -0,0 +1,151 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"service-a/middleware"
+	"service-a/service"
+	"service-a/util/disconnect"
+	"service-a/util/logging"
+	"service-a/util/tracing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// PingStream relays the PingStream call of service-b as Server-Sent Events.
+//
+// Every pong message is sent as a "pong" event. A failure after the stream
+// started is sent as an "error" event holding the problem details, and the
+// stream ends with a "done" event. When the client disconnects the call on
+// service-b is cancelled right away, not on the next failed write.
+func (api *Api) PingStream(c *fiber.Ctx) error {
+	const op = "api.Api.PingStream"
+
+	// Start span, it lasts until the stream is over
+	ctx, span := api.tracer.Start(c.UserContext(), op)
+
+	span.SetAttributes(
+		attribute.String("api.endpoint", "/ping/stream"),
+		attribute.String("api.method", "GET"),
+	)
+
+	// Get logger with trace id
+	logger := logging.LogWithTrace(ctx, api.logger)
+
+	// Request values are copied, the writer runs after the handler returned
+	params := &service.PingStreamParams{
+		PingMessage: strings.Clone(c.Query("message")),
+		Count:       c.QueryInt("count", 5),
+		Interval:    time.Duration(c.QueryInt("interval_ms", 1000)) * time.Millisecond,
+	}
+
+	err := params.Validate()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
+
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	logger.WithFields(logrus.Fields{
+		"[op]":   op,
+		"params": fmt.Sprintf("%+v", params),
+	}).Info()
+
+	problem := middleware.NewProblem(c, fiber.StatusInternalServerError, "")
+
+	// The connection is watched from the writer, once the handler returned
+	conn := c.Context().Conn()
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer span.End()
+
+		// The writer runs outside the handler chain, a panic only ends the stream
+		defer func() {
+			if value := recover(); value != nil {
+				tracing.RecordPanic(ctx, api.logger, value)
+
+				_ = writeEvent(w, "error", "", problem.WithError(fiber.ErrInternalServerError))
+			}
+		}()
+
+		// The server does not notice a closed connection before writing to it
+		ctx, stop := disconnect.Watch(ctx, conn)
+		defer stop()
+
+		sent := 0
+		err := api.service.PingStream(ctx, params, func(result *service.PingStreamResult) error {
+			err := writeEvent(w, "pong", fmt.Sprint(result.Sequence), result)
+			if err != nil {
+				return fmt.Errorf("client went away: %w", err)
+			}
+
+			sent++
+			span.AddEvent("sse.event_sent", trace.WithAttributes(
+				attribute.Int("api.stream.sequence", result.Sequence),
+			))
+
+			return nil
+		})
+
+		span.SetAttributes(
+			attribute.Int("api.output.sent", sent),
+		)
+
+		if disconnect.Gone(ctx) {
+			span.AddEvent("client_disconnected")
+		}
+
+		if err != nil {
+			// Record the error in the span
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+
+			logger.WithFields(logrus.Fields{
+				"[op]":  op,
+				"sent":  sent,
+				"error": err.Error(),
+			}).Error()
+
+			_ = writeEvent(w, "error", "", problem.WithError(err))
+
+			return
+		}
+
+		_ = writeEvent(w, "done", "", fiber.Map{"sent": sent})
+
+		span.SetStatus(codes.Ok, "request completed successfully")
+	})
+
+	return nil
+}
+
+// writeEvent writes a single Server-Sent Event with a JSON data line and
+// flushes it to the client
+func writeEvent(w *bufio.Writer, event, id string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if id != "" {
+		fmt.Fprintf(w, "id: %s\n", id)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+
+	return w.Flush()
+}
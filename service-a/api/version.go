@@ -0,0 +1,61 @@
+package api
+
+import (
+	"service-a/middleware"
+	"service-a/service"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// API versions, the unversioned routes are deprecated aliases of v1
+const (
+	apiV1 = "v1"
+	apiV2 = "v2"
+)
+
+// pingResponseV2 is the ping response from v2 on. The result is wrapped so
+// that metadata can be added without touching the fields of the result.
+type pingResponseV2 struct {
+	Data *service.PingResult `json:"data"`
+	Meta responseMeta        `json:"meta"`
+}
+
+type responseMeta struct {
+	APIVersion string `json:"api_version"`
+	TraceID    string `json:"trace_id,omitempty"`
+}
+
+// sendPingResult writes the ping result in the shape of the API version the
+// request was routed to
+func sendPingResult(c *fiber.Ctx, result *service.PingResult) error {
+	version := middleware.APIVersion(c)
+	if version != apiV2 {
+		return c.JSON(result)
+	}
+
+	meta := responseMeta{
+		APIVersion: version,
+	}
+
+	// A cached response would repeat the trace id of the request that filled
+	// the cache, the X-Trace-Id header always holds the current one
+	if sc := trace.SpanContextFromContext(c.UserContext()); sc.IsValid() && !middleware.Cacheable(c) {
+		meta.TraceID = sc.TraceID().String()
+	}
+
+	return c.JSON(pingResponseV2{
+		Data: result,
+		Meta: meta,
+	})
+}
+
+// versionPrefix returns the path prefix of an API version, "" for the
+// unversioned routes
+func versionPrefix(version string) string {
+	if version == "" {
+		return ""
+	}
+
+	return "/" + version
+}
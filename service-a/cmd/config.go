@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"service-a/util/config"
+)
+
+func configCmd() {
+	cmds := map[string]func(){
+		"validate": configValidate,
+		"print":    configPrint,
+	}
+
+	if cmdFunc, ok := cmds[flag.Arg(1)]; ok {
+		cmdFunc()
+	} else {
+		help()
+		os.Exit(2)
+	}
+}
+
+func configValidate() {
+	_, err := config.LoadConfig(configFile, configEnv)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+
+		os.Exit(1)
+	}
+
+	fmt.Println("configuration is valid")
+}
+
+func configPrint() {
+	settings, err := config.Explain(configFile, configEnv)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+
+		os.Exit(1)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+
+	for _, setting := range settings {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", setting.Key, setting.Value, setting.Source)
+	}
+
+	w.Flush()
+}
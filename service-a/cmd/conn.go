@@ -2,33 +2,46 @@ package main
 
 import (
 	"fmt"
+	"net"
+	"strconv"
+	"strings"
 
 	"service-a/adapter/service_b_adapter"
+	"service-a/interceptor"
+	"service-a/util/auth"
 	"service-a/util/config"
 
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
-func createServiceBAdapter(config config.ServiceB, logger *logrus.Logger, tracer trace.Tracer) (*service_b_adapter.Adapter, error) {
-	address := fmt.Sprintf("%s:%d", config.Host, config.Port)
+func createServiceBAdapter(config config.ServiceB, forwarder *auth.Forwarder, logger *logrus.Logger, tracer trace.Tracer) (*service_b_adapter.Adapter, error) {
+	// A unix:///path/to.sock host is dialed as is
+	address := config.Host
+	if !strings.HasPrefix(address, "unix://") {
+		address = net.JoinHostPort(config.Host, strconv.Itoa(config.Port))
+	}
 
 	conn, err := grpc.NewClient(
 		address,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 		grpc.WithStatsHandler(otelgrpc.NewClientHandler(
 			otelgrpc.WithPropagators(propagation.TraceContext{}),
+			otelgrpc.WithFilter(filters.Not(filters.HealthCheck())),
 		)),
+		grpc.WithChainUnaryInterceptor(interceptor.ForwardPrincipalUnary(forwarder)),
+		grpc.WithChainStreamInterceptor(interceptor.ForwardPrincipalStream(forwarder)),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error connecting to %s grpc server: %w", config.Name, err)
 	}
 
-	grpcAdapter := service_b_adapter.NewAdapter(config.Name, logger, tracer, conn)
+	grpcAdapter := service_b_adapter.NewAdapter(config, logger, tracer, conn)
 
 	return grpcAdapter, nil
 }
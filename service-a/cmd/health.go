@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"service-a/adapter/service_b_adapter"
+	"service-a/util/config"
+	"service-a/util/health"
+	"service-a/util/tracing"
+)
+
+// createReadinessChecker registers the checks behind /readyz
+func createReadinessChecker(
+	config config.Health,
+	watcher *config.Watcher,
+	serviceBAdapter *service_b_adapter.Adapter,
+) *health.Checker {
+	checker := health.NewChecker(config.Timeout)
+
+	// The config on disk must be the one running
+	checker.Register("config", func(ctx context.Context) error {
+		if err := watcher.Err(); err != nil {
+			return fmt.Errorf("last reload rejected: %w", err)
+		}
+
+		if pending := watcher.Pending(); len(pending) > 0 {
+			return fmt.Errorf("restart needed to apply %s", strings.Join(pending, ", "))
+		}
+
+		return nil
+	})
+
+	checker.Register("service_b", serviceBAdapter.Check)
+
+	checker.Register("telemetry", func(ctx context.Context) error {
+		return tracing.ExporterStatus()
+	})
+
+	return checker
+}
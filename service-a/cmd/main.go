@@ -7,13 +7,23 @@ import (
 	"strings"
 )
 
+// Global flags shared by all commands
+var (
+	configFile string
+	configEnv  string
+)
+
 func main() {
+	flag.StringVar(&configFile, "config", "", "path to the config file (json, yaml or toml)")
+	flag.StringVar(&configEnv, "env", os.Getenv("SERVICE_A_ENV"), "environment overlay to merge on top of the config file")
+
 	flag.Usage = help
 	flag.Parse()
 
 	cmds := map[string]func(){
-		"help":  help,
-		"start": start,
+		"help":   help,
+		"start":  start,
+		"config": configCmd,
 	}
 
 	if cmdFunc, ok := cmds[flag.Arg(0)]; ok {
@@ -35,6 +45,13 @@ func help() {
 			fmt.Sprintf(divider, strings.Repeat("-", 30), strings.Repeat("-", 50)) +
 			fmt.Sprintf(row, "help", "show this help message") +
 			fmt.Sprintf(row, "start", "start the server") +
+			fmt.Sprintf(row, "config validate", "validate the configuration, exit 1 if invalid") +
+			fmt.Sprintf(row, "config print", "show the effective configuration and its sources") +
+			fmt.Sprintf(divider, strings.Repeat("-", 30), strings.Repeat("-", 50)) +
+			fmt.Sprintf(header, "Flags (before the command)", "Description") +
+			fmt.Sprintf(divider, strings.Repeat("-", 30), strings.Repeat("-", 50)) +
+			fmt.Sprintf(row, "--config <file>", "config file, default ./config.{json,yaml,yml,toml}") +
+			fmt.Sprintf(row, "--env <name>", "merge config.<name>.* on top (or SERVICE_A_ENV)") +
 			fmt.Sprintf(divider, strings.Repeat("_", 30), strings.Repeat("_", 50))
 
 	fmt.Fprintln(os.Stderr, output)
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+
+	"service-a/adapter/service_b_adapter"
+	"service-a/middleware"
+	"service-a/service"
+	"service-a/util/config"
+	"service-a/util/logging"
+	"service-a/util/tracing"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// watchConfig starts hot reloading the config file and applies the live
+// logging, tracing, CORS, request timeout, rate limit, idempotency, response
+// cache, API versioning, request validation, service-b call and batch settings
+// whenever it changes
+func watchConfig(
+	ctx context.Context,
+	current config.Config,
+	logger *logrus.Logger,
+	tracer trace.Tracer,
+	redactingFormatter *logging.RedactingFormatter,
+	samplingFormatter *logging.SamplingFormatter,
+	sampler *tracing.RatioSampler,
+	corsPolicy *middleware.CORS,
+	deadlines *middleware.Deadlines,
+	rateLimiter *middleware.RateLimiter,
+	idem *middleware.Idempotency,
+	responseCache *middleware.ResponseCache,
+	versions *middleware.Versions,
+	validator *middleware.RequestValidator,
+	serviceBAdapter *service_b_adapter.Adapter,
+	service *service.Service,
+) *config.Watcher {
+	const op = "main.watchConfig"
+
+	watcher := config.NewWatcher(configFile, configEnv, current, logger, tracer)
+
+	watcher.Subscribe(func(config config.Config) {
+		level, _ := logrus.ParseLevel(config.Logging.Level)
+		logger.SetLevel(level)
+
+		redactingFormatter.SetFields(config.Logging.Redact)
+		samplingFormatter.SetConfig(logging.SamplerConfig{
+			Interval:    config.Logging.Sampling.Interval,
+			Limit:       config.Logging.Sampling.Limit,
+			KeepSampled: config.Logging.Sampling.KeepSampled,
+		})
+
+		sampler.SetRatio(config.OtelTracer.SampleRatio)
+
+		corsPolicy.SetConfig(config.CORS)
+		deadlines.SetConfig(config.Timeouts)
+		rateLimiter.SetConfig(config.RateLimit)
+		idem.SetConfig(config.Idempotency)
+		responseCache.SetConfig(config.Cache)
+		versions.SetConfig(config.Versioning)
+		validator.SetConfig(config.OpenAPI)
+
+		serviceBAdapter.SetConfig(config.ServiceB)
+		service.SetConfig(config.Batch)
+	})
+
+	go func() {
+		if err := watcher.Run(ctx); err != nil {
+			logger.WithFields(logrus.Fields{
+				"[op]":  op,
+				"scope": "WatchConfig",
+				"err":   err.Error(),
+			}).Error()
+		}
+	}()
+
+	return watcher
+}
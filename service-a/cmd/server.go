@@ -1,38 +1,44 @@
 package main
 
 import (
-	"fmt"
 	"log"
 	"os"
 
 	"service-a/api"
+	"service-a/middleware"
+	"service-a/util/listener"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
 )
 
-func runRestServer(port int, api *api.Api) {
+func runRestServer(host string, port int, api *api.Api, corsPolicy *middleware.CORS) *fiber.App {
 	// Init fiber app
 	app := fiber.New()
 
-	// CORS middleware configuration
-	corsConfig := cors.Config{
-		AllowOrigins: "*",
-		AllowHeaders: "Origin, Content-Type, Accept, Authorization",
-	}
-
-	app.Use(cors.New(corsConfig))
+	// CORS middleware, ahead of every route so that rejections stay readable
+	app.Use(corsPolicy.Handler())
 
 	// Endpoint definitions
 	app = api.SetupRoutes(app)
 
-	// start the server
-	err := app.Listen(fmt.Sprintf(":%d", port))
+	// Listen at configured address
+	ln, err := listener.Listen(host, port)
 	if err != nil {
-		log.Printf("failed to listen at port: %v!", port)
+		log.Printf("failed to listen at %s port %d: %v", host, port, err)
 
 		os.Exit(1)
 	}
 
-	log.Printf("rest server started successfully 🚀")
+	// Serve the rest server
+	go func() {
+		log.Printf("rest server started successfully 🚀")
+
+		if err := app.Listener(ln); err != nil {
+			log.Printf("failed to serve at %s: %v", ln.Addr(), err)
+
+			os.Exit(1)
+		}
+	}()
+
+	return app
 }
@@ -6,10 +6,18 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"syscall"
+	"time"
 
 	"service-a/api"
+	"service-a/middleware"
 	"service-a/service"
+	"service-a/util/auth"
+	"service-a/util/cache"
 	"service-a/util/config"
+	"service-a/util/idempotency"
+	"service-a/util/logging"
+	"service-a/util/ratelimit"
 	"service-a/util/tracing"
 
 	"github.com/sirupsen/logrus"
@@ -28,7 +36,7 @@ func start() {
 	logger.Out = os.Stdout
 
 	// --- Load config ---
-	config, err := config.LoadConfig(".")
+	config, err := config.LoadConfig(configFile, configEnv)
 	if err != nil {
 		logger.WithFields(logrus.Fields{
 			"[op]":  op,
@@ -39,8 +47,23 @@ func start() {
 		os.Exit(1)
 	}
 
+	// --- Apply log level ---
+	level, _ := logrus.ParseLevel(config.Logging.Level)
+	logger.Level = level
+
+	// --- Enable log redaction and sampling ---
+	redactingFormatter := logging.NewRedactingFormatter(logger.Formatter, config.Logging.Redact)
+	samplingFormatter := logging.NewSamplingFormatter(redactingFormatter, logging.SamplerConfig{
+		Interval:    config.Logging.Sampling.Interval,
+		Limit:       config.Logging.Sampling.Limit,
+		KeepSampled: config.Logging.Sampling.KeepSampled,
+	})
+	logger.Formatter = samplingFormatter
+
 	// --- Init otel tracer ---
-	cleanup, err := tracing.InitTracer(config.OtelTracer.Name, config.OtelTracer.Endpoint)
+	sampler := tracing.NewRatioSampler(config.OtelTracer.SampleRatio)
+
+	cleanup, err := tracing.InitTracer(config.OtelTracer.Name, config.OtelTracer.Endpoint, config.OtelTracer.Headers, sampler)
 	if err != nil {
 		logger.WithFields(logrus.Fields{
 			"[op]":  op,
@@ -48,45 +71,163 @@ func start() {
 			"err":   err.Error(),
 		}).Error()
 	}
-	defer func() {
-		if err := cleanup(context.Background()); err != nil {
-			logger.WithFields(logrus.Fields{
-				"[op]":  op,
-				"scope": "CleanupTracer",
-				"err":   err.Error(),
-			}).Error()
-		}
-	}()
-
 	tracer := tracing.GetTracer(config.OtelTracer.Name)
 
+	// --- Init otel meter ---
+	meterCleanup, err := tracing.InitMeter(config.OtelTracer.Name, config.OtelTracer.Endpoint, config.OtelTracer.Headers, config.OtelTracer.MetricInterval)
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"[op]":  op,
+			"scope": "InitMeter",
+			"err":   err.Error(),
+		}).Error()
+	}
+	meter := tracing.GetMeter(config.OtelTracer.Name)
+
 	logger.WithFields(logrus.Fields{
 		"[op]":   op,
 		"config": fmt.Sprintf("%+v", config),
 	}).Infof("Starting '%s' service ...", config.App.Name)
 
+	// --- Cancel on SIGINT or SIGTERM ---
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// --- Report suppressed log entries of quiet groups ---
+	go samplingFormatter.Run(ctx)
+
+	// --- Init CORS policy ---
+	corsPolicy := middleware.NewCORS(config.CORS)
+
+	// --- Init request deadlines ---
+	deadlines := middleware.NewDeadlines(config.Timeouts)
+
+	// --- Init rate limiter ---
+	rateLimiter, err := middleware.NewRateLimiter(config.RateLimit, ratelimit.NewMemoryStore(config.RateLimit.MaxBuckets), logger, meter)
+	if err != nil {
+		log.Printf("failed to create rate limiter: %v", err)
+		os.Exit(1)
+	}
+
+	// --- Init idempotency keys ---
+	idem := middleware.NewIdempotency(config.Idempotency, idempotency.NewMemoryStore(config.Idempotency.MaxEntries, config.Idempotency.MaxBytes), logger)
+
+	// --- Init response cache ---
+	responseCache, err := middleware.NewResponseCache(config.Cache, cache.NewLRU(config.Cache.MaxEntries, config.Cache.MaxBytes), logger, meter)
+	if err != nil {
+		log.Printf("failed to create response cache: %v", err)
+		os.Exit(1)
+	}
+
+	// --- Init API versions ---
+	versions, err := middleware.NewVersions(config.Versioning, meter)
+	if err != nil {
+		log.Printf("failed to create api versions: %v", err)
+		os.Exit(1)
+	}
+
+	// --- Init authentication ---
+	authenticator, err := auth.NewAuthenticator(config.Auth)
+	if err != nil {
+		log.Printf("failed to create authenticator: %v", err)
+		os.Exit(1)
+	}
+
+	// --- Build OpenAPI document ---
+	document, err := api.NewDocument(config)
+	if err != nil {
+		log.Printf("failed to build openapi document: %v", err)
+		os.Exit(1)
+	}
+
+	// --- Init request validation ---
+	validator, err := middleware.NewRequestValidator(config.OpenAPI, document, logger)
+	if err != nil {
+		log.Printf("failed to create request validator: %v", err)
+		os.Exit(1)
+	}
+
 	// --- Init service-b adapter ---
-	serviceBAdapter, err := createServiceBAdapter(config.ServiceB, logger, tracer)
+	serviceBAdapter, err := createServiceBAdapter(config.ServiceB, auth.NewForwarder(config.Auth.Forward), logger, tracer)
 	if err != nil {
 		log.Printf("failed to create service-b adapter: %v", err)
 		os.Exit(1)
 	}
 
 	// --- Init service layer ---
-	service := service.NewService(logger, tracer, serviceBAdapter)
+	service := service.NewService(config.Batch, logger, tracer, serviceBAdapter)
+
+	// --- Watch config for changes ---
+	watcher := watchConfig(ctx, config, logger, tracer, redactingFormatter, samplingFormatter, sampler, corsPolicy, deadlines, rateLimiter, idem, responseCache, versions, validator, serviceBAdapter, service)
+
+	// --- Init readiness checks ---
+	checker := createReadinessChecker(config.Health, watcher, serviceBAdapter)
 
 	// --- Init api layer ---
-	restApi := api.NewApi(logger, tracer, service)
+	restApi := api.NewApi(config, logger, tracer, service, checker, deadlines, rateLimiter, idem, responseCache, versions, authenticator, document, validator)
 
 	// --- Run servers ---
-	runRestServer(config.App.Port, restApi)
+	app := runRestServer(config.App.Host, config.App.Port, restApi, corsPolicy)
+
+	// --- Block until SIGINT or SIGTERM is received ---
+	<-ctx.Done()
+	stop()
+
+	logger.WithFields(logrus.Fields{
+		"[op]":    op,
+		"timeout": config.App.ShutdownTimeout.String(),
+	}).Info("Shutting down, draining in-flight requests ...")
+
+	// --- Drain and flush share the same deadline ---
+	deadline := time.Now().Add(config.App.ShutdownTimeout)
+
+	// --- Stop accepting requests and drain in-flight ones ---
+	if err := app.ShutdownWithTimeout(config.App.ShutdownTimeout); err != nil {
+		logger.WithFields(logrus.Fields{
+			"[op]":  op,
+			"scope": "ShutdownRestServer",
+			"err":   err.Error(),
+		}).Error()
+	}
+
+	// --- Close service-b connection ---
+	if err := serviceBAdapter.Close(); err != nil {
+		logger.WithFields(logrus.Fields{
+			"[op]":  op,
+			"scope": "CloseServiceBAdapter",
+			"err":   err.Error(),
+		}).Error()
+	}
+
+	// --- Report the log entries still suppressed ---
+	samplingFormatter.Flush()
+
+	// --- Flush telemetry ---
+	if cleanup != nil {
+		flushCtx, cancel := context.WithDeadline(context.Background(), deadline)
+		defer cancel()
+
+		if err := cleanup(flushCtx); err != nil {
+			logger.WithFields(logrus.Fields{
+				"[op]":  op,
+				"scope": "CleanupTracer",
+				"err":   err.Error(),
+			}).Error()
+		}
+	}
 
-	// --- Wait for signal ---
-	ch := make(chan os.Signal, 1)
-	signal.Notify(ch, os.Interrupt)
+	if meterCleanup != nil {
+		flushCtx, cancel := context.WithDeadline(context.Background(), deadline)
+		defer cancel()
 
-	// --- Block until signal is received ---
-	<-ch
+		if err := meterCleanup(flushCtx); err != nil {
+			logger.WithFields(logrus.Fields{
+				"[op]":  op,
+				"scope": "CleanupMeter",
+				"err":   err.Error(),
+			}).Error()
+		}
+	}
 
 	log.Printf("end of program...")
 }
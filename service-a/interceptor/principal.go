@@ -0,0 +1,52 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+
+	"service-a/util/auth"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// ForwardPrincipalUnary creates a client interceptor that asserts the
+// principal of the request to service-b with a token issued by forwarder
+func ForwardPrincipalUnary(forwarder *auth.Forwarder) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, err := withPrincipalToken(ctx, forwarder)
+		if err != nil {
+			return err
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// ForwardPrincipalStream is the stream counterpart of ForwardPrincipalUnary
+func ForwardPrincipalStream(forwarder *auth.Forwarder) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, err := withPrincipalToken(ctx, forwarder)
+		if err != nil {
+			return nil, err
+		}
+
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
+// withPrincipalToken adds the authorization metadata of the principal in ctx.
+// Calls made without a principal are sent as they are.
+func withPrincipalToken(ctx context.Context, forwarder *auth.Forwarder) (context.Context, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok || !forwarder.Enabled() {
+		return ctx, nil
+	}
+
+	token, err := forwarder.Token(principal)
+	if err != nil {
+		return ctx, fmt.Errorf("failed to issue forwarded token: %w", err)
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token), nil
+}
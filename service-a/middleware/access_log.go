@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"time"
+
+	"service-a/util/logging"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AccessLog creates a middleware that writes one summary line per request
+func AccessLog(logger *logrus.Logger) fiber.Handler {
+	const op = "middleware.AccessLog"
+
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		// Forward to next handler
+		err := c.Next()
+
+		// Let the error handler decide the status before it is logged
+		if err != nil {
+			if herr := c.App().ErrorHandler(c, err); herr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		ctx := c.UserContext()
+		sc := trace.SpanContextFromContext(ctx)
+		status := c.Response().StatusCode()
+
+		entry := logging.LogWithTrace(ctx, logger).WithFields(logrus.Fields{
+			"[op]":       op,
+			"method":     c.Method(),
+			"route":      routePath(c),
+			"path":       c.Path(),
+			"status":     status,
+			"latency":    time.Since(start).String(),
+			"bytes":      bodySize(c),
+			"peer":       c.IP(),
+			"user_agent": c.Get(fiber.HeaderUserAgent),
+			"trace_id":   sc.TraceID().String(),
+			"span_id":    sc.SpanID().String(),
+		})
+
+		switch {
+		case status >= fiber.StatusInternalServerError:
+			entry.Error("request completed")
+		case status >= fiber.StatusBadRequest:
+			entry.Warn("request completed")
+		default:
+			entry.Info("request completed")
+		}
+
+		return nil
+	}
+}
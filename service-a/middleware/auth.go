@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"errors"
+
+	"service-a/util/auth"
+	"service-a/util/logging"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Auth creates a middleware that authenticates requests with an API key
+// (X-Api-Key) or a bearer token, and puts the principal into the request
+// context. Requests without valid credentials get a 401 problem response.
+func Auth(authenticator *auth.Authenticator, logger *logrus.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		const op = "middleware.Auth"
+
+		ctx := c.UserContext()
+
+		principal, err := authenticator.Authenticate(c.Get(HeaderAPIKey), c.Get(fiber.HeaderAuthorization))
+		if err != nil {
+			logging.LogWithTrace(ctx, logger).WithFields(logrus.Fields{
+				"[op]":  op,
+				"path":  c.Path(),
+				"error": err.Error(),
+			}).Warn("authentication failed")
+
+			trace.SpanFromContext(ctx).AddEvent("authentication_failed")
+
+			detail := "Invalid credentials"
+			if errors.Is(err, auth.ErrNoCredentials) {
+				detail = "Authentication required, send an X-Api-Key header or a bearer token"
+			}
+
+			c.Set(fiber.HeaderWWWAuthenticate, `Bearer, ApiKey header="`+HeaderAPIKey+`"`)
+
+			return fiber.NewError(fiber.StatusUnauthorized, detail)
+		}
+
+		// The subject identifies the caller, the credentials never reach the span
+		trace.SpanFromContext(ctx).SetAttributes(
+			semconv.EnduserID(principal.Subject),
+			attribute.String("auth.method", principal.Method),
+		)
+
+		c.SetUserContext(auth.WithPrincipal(ctx, principal))
+
+		// Forward to next handler
+		return c.Next()
+	}
+}
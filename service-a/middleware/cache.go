@@ -0,0 +1,235 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"service-a/util/auth"
+	"service-a/util/cache"
+	"service-a/util/config"
+	"service-a/util/logging"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const cacheableLocal = "cache.cacheable"
+
+// ResponseCache serves GET responses from a pluggable cache.Store for the TTL
+// of their route, and answers conditional requests with 304 Not Modified
+type ResponseCache struct {
+	logger *logrus.Logger
+	store  cache.Store
+
+	requests metric.Int64Counter
+
+	mu     sync.RWMutex
+	config config.Cache
+}
+
+// NewResponseCache creates the response cache of the cache config, keeping
+// the responses in store
+func NewResponseCache(config config.Cache, store cache.Store, logger *logrus.Logger, meter metric.Meter) (*ResponseCache, error) {
+	requests, err := meter.Int64Counter("http.server.cache.requests",
+		metric.WithDescription("Requests looked up in the response cache, by route and result"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cache counter: %w", err)
+	}
+
+	return &ResponseCache{
+		logger: logger,
+		store:  store,
+
+		requests: requests,
+
+		config: config,
+	}, nil
+}
+
+// SetConfig replaces the cache config, applied to the following requests
+func (rc *ResponseCache) SetConfig(config config.Cache) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.config = config
+}
+
+// ttl returns how long the responses of route are cached, 0 when they are not
+func (rc *ResponseCache) ttl(route string) time.Duration {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+
+	if !rc.config.Enabled {
+		return 0
+	}
+
+	if ttl, ok := rc.config.Routes[route]; ok {
+		return ttl
+	}
+
+	return rc.config.TTL
+}
+
+// Handler creates a middleware caching the 200 responses of a GET route. It
+// must be registered on the route itself, not with Use, to see the matched
+// route. A hit is sent without running the handler; a request with
+// "Cache-Control: no-cache" skips the lookup but refreshes the entry.
+func (rc *ResponseCache) Handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		const op = "middleware.ResponseCache.Handler"
+
+		ttl := rc.ttl(routeKey(c))
+		if ttl <= 0 || c.Method() != fiber.MethodGet {
+			return c.Next()
+		}
+
+		ctx := c.UserContext()
+		span := trace.SpanFromContext(ctx)
+		now := time.Now()
+
+		key := cacheKey(c)
+
+		result := "miss"
+		if strings.Contains(c.Get(fiber.HeaderCacheControl), "no-cache") {
+			result = "bypass"
+		} else {
+			entry, err := rc.store.Get(ctx, key, now)
+			if err != nil {
+				logging.LogWithTrace(ctx, rc.logger).WithFields(logrus.Fields{
+					"[op]":  op,
+					"error": err.Error(),
+				}).Warn("cache store failed, request let through")
+			}
+
+			if entry != nil {
+				rc.record(c, span, "hit")
+
+				span.SetAttributes(attribute.Int64("cache.age_ms", now.Sub(entry.Stored).Milliseconds()))
+
+				c.Set(fiber.HeaderAge, strconv.Itoa(int(now.Sub(entry.Stored).Seconds())))
+
+				return rc.send(c, entry, now)
+			}
+		}
+
+		rc.record(c, span, result)
+
+		c.Locals(cacheableLocal, true)
+
+		// Forward to next handler
+		err := c.Next()
+		if err != nil || c.Response().StatusCode() != fiber.StatusOK {
+			return err
+		}
+
+		// Freshness starts once the response is known
+		stored := time.Now()
+
+		body := c.Response().Body()
+		entry := cache.Entry{
+			Status:      fiber.StatusOK,
+			ContentType: string(c.Response().Header.ContentType()),
+			Body:        bytes.Clone(body),
+			ETag:        etag(body),
+			Stored:      stored,
+			Expires:     stored.Add(ttl),
+		}
+
+		err = rc.store.Set(ctx, key, entry)
+		if err != nil {
+			logging.LogWithTrace(ctx, rc.logger).WithFields(logrus.Fields{
+				"[op]":  op,
+				"error": err.Error(),
+			}).Warn("cache store failed, response not cached")
+		}
+
+		return rc.send(c, &entry, stored)
+	}
+}
+
+// cacheKey identifies the response of the request. Versions share the route
+// settings but not the responses, and an authenticated caller only gets back
+// its own responses.
+func cacheKey(c *fiber.Ctx) string {
+	key := c.Route().Path + "?" + string(c.Request().URI().QueryString())
+
+	if principal, ok := auth.PrincipalFromContext(c.UserContext()); ok {
+		key = principal.Method + ":" + principal.Subject + "|" + key
+	}
+
+	return key
+}
+
+// Cacheable reports whether the response to the request may be stored and
+// served to other requests, it must then hold nothing specific to this one
+func Cacheable(c *fiber.Ctx) bool {
+	cacheable, _ := c.Locals(cacheableLocal).(bool)
+
+	return cacheable
+}
+
+// send writes the validators and freshness of entry, and its body unless the
+// client already holds it
+func (rc *ResponseCache) send(c *fiber.Ctx, entry *cache.Entry, now time.Time) error {
+	// A response to an authenticated request is not for shared caches
+	visibility := "public"
+	if _, ok := auth.PrincipalFromContext(c.UserContext()); ok {
+		visibility = "private"
+	}
+
+	c.Set(fiber.HeaderETag, entry.ETag)
+	c.Set(fiber.HeaderCacheControl, fmt.Sprintf("%s, max-age=%d", visibility, int(entry.Expires.Sub(now).Seconds())))
+
+	if matchesETag(c.Get(fiber.HeaderIfNoneMatch), entry.ETag) {
+		c.Response().ResetBody()
+
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, entry.ContentType)
+
+	return c.Status(entry.Status).Send(entry.Body)
+}
+
+func (rc *ResponseCache) record(c *fiber.Ctx, span trace.Span, result string) {
+	rc.requests.Add(c.UserContext(), 1, metric.WithAttributes(
+		attribute.String("http.route", routePath(c)),
+		attribute.String("cache.result", result),
+	))
+
+	span.SetAttributes(
+		attribute.Bool("cache.hit", result == "hit"),
+		attribute.String("cache.result", result),
+	)
+}
+
+// etag is a strong validator of body
+func etag(body []byte) string {
+	sum := sha256.Sum256(body)
+
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// matchesETag tells whether an If-None-Match header holds tag, compared
+// weakly as RFC 9110 requires
+func matchesETag(header, tag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == tag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"service-a/util/auth"
+	"service-a/util/cache"
+	"service-a/util/config"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+func TestResponseCacheKeepsPrincipalsApart(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	rc, err := NewResponseCache(config.Cache{Enabled: true, TTL: time.Minute},
+		cache.NewLRU(10, 1024), logger, noop.NewMeterProvider().Meter("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := fiber.New()
+	app.Get("/ping", func(c *fiber.Ctx) error {
+		// Stands in for the authentication middleware
+		if subject := c.Get("X-Subject"); subject != "" {
+			c.SetUserContext(auth.WithPrincipal(c.UserContext(),
+				auth.Principal{Subject: subject, Method: auth.MethodAPIKey}))
+		}
+
+		return c.Next()
+	}, rc.Handler(), func(c *fiber.Ctx) error {
+		return c.SendString("pong for " + c.Get("X-Subject"))
+	})
+
+	tests := []struct {
+		subject string
+		want    string
+	}{
+		{subject: "alice", want: "pong for alice"},
+		{subject: "bob", want: "pong for bob"},
+		{subject: "alice", want: "pong for alice"},
+		{subject: "", want: "pong for "},
+		{subject: "bob", want: "pong for bob"},
+	}
+
+	for i, tt := range tests {
+		req := httptest.NewRequest(fiber.MethodGet, "/ping", nil)
+		if tt.subject != "" {
+			req.Header.Set("X-Subject", tt.subject)
+		}
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		if string(body) != tt.want {
+			t.Fatalf("request %d as %q = %q, want %q", i, tt.subject, body, tt.want)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"strings"
+	"sync"
+
+	"service-a/util/config"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+)
+
+// CORS applies the cors config to cross-origin requests. The config is
+// checked when it is loaded, so building the policy cannot fail.
+type CORS struct {
+	mu      sync.RWMutex
+	handler fiber.Handler
+}
+
+// NewCORS creates the CORS policy of the cors config
+func NewCORS(config config.CORS) *CORS {
+	return &CORS{
+		handler: newCORSHandler(config),
+	}
+}
+
+// SetConfig replaces the cors config, applied to the following requests
+func (policy *CORS) SetConfig(config config.CORS) {
+	handler := newCORSHandler(config)
+
+	policy.mu.Lock()
+	defer policy.mu.Unlock()
+
+	policy.handler = handler
+}
+
+// Handler creates a middleware answering preflight requests and setting the
+// CORS headers of the others. It must run ahead of any middleware that may
+// reject the request, so that browsers can read the rejection.
+func (policy *CORS) Handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		policy.mu.RLock()
+		handler := policy.handler
+		policy.mu.RUnlock()
+
+		return handler(c)
+	}
+}
+
+func newCORSHandler(config config.CORS) fiber.Handler {
+	// No allowed origin, browsers block every cross-origin request
+	if len(config.AllowOrigins) == 0 {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	return cors.New(cors.Config{
+		AllowOrigins:     strings.Join(config.AllowOrigins, ","),
+		AllowMethods:     strings.Join(config.AllowMethods, ","),
+		AllowHeaders:     strings.Join(config.AllowHeaders, ","),
+		ExposeHeaders:    strings.Join(config.ExposeHeaders, ","),
+		AllowCredentials: config.AllowCredentials,
+		MaxAge:           int(config.MaxAge.Seconds()),
+	})
+}
@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"service-a/util/config"
+	"service-a/util/disconnect"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HeaderRequestTimeout lets a client ask for a shorter (or, up to
+// timeouts.max, longer) deadline, in milliseconds or as a duration like "1.5s"
+const HeaderRequestTimeout = "X-Request-Timeout"
+
+// Deadlines sets the deadline of requests from the timeouts config. The
+// deadline is carried by the request context, so it bounds every layer and
+// is propagated to service-b as the gRPC deadline.
+type Deadlines struct {
+	mu     sync.RWMutex
+	config config.Timeouts
+}
+
+// NewDeadlines creates the deadlines of the timeouts config
+func NewDeadlines(config config.Timeouts) *Deadlines {
+	return &Deadlines{
+		config: config,
+	}
+}
+
+// SetConfig replaces the timeouts config, applied to the following requests
+func (deadlines *Deadlines) SetConfig(config config.Timeouts) {
+	deadlines.mu.Lock()
+	defer deadlines.mu.Unlock()
+
+	deadlines.config = config
+}
+
+// Handler creates a middleware setting the deadline of the request, and
+// cancelling it when the client closes its connection. It must be registered
+// on the route itself, not with Use, to see the matched route. Streamed
+// responses outlive their handler and must not use it.
+func (deadlines *Deadlines) Handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		timeout, err := deadlines.timeout(routeKey(c), c.Get(HeaderRequestTimeout))
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+
+		// Work for a client that went away is abandoned
+		ctx, stop := disconnect.Watch(c.UserContext(), c.Context().Conn())
+		defer stop()
+
+		span := trace.SpanFromContext(ctx)
+
+		if timeout > 0 {
+			var cancel context.CancelFunc
+
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			span.SetAttributes(
+				attribute.Int64("request.timeout_ms", timeout.Milliseconds()),
+			)
+		}
+
+		c.SetUserContext(ctx)
+
+		// Forward to next handler
+		err = c.Next()
+
+		if disconnect.Gone(ctx) {
+			span.AddEvent("client_disconnected")
+		}
+
+		return err
+	}
+}
+
+// timeout returns the deadline of a request to route, given the value of its
+// X-Request-Timeout header
+func (deadlines *Deadlines) timeout(route, header string) (time.Duration, error) {
+	deadlines.mu.RLock()
+	defer deadlines.mu.RUnlock()
+
+	timeout := deadlines.config.Default
+	if routeTimeout, ok := deadlines.config.Routes[route]; ok {
+		timeout = routeTimeout
+	}
+
+	if header == "" || deadlines.config.Max <= 0 {
+		return timeout, nil
+	}
+
+	requested, err := parseTimeout(header)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s header: %s", HeaderRequestTimeout, err)
+	}
+
+	return min(requested, deadlines.config.Max), nil
+}
+
+// parseTimeout reads a timeout in milliseconds or as a duration
+func parseTimeout(value string) (time.Duration, error) {
+	var timeout time.Duration
+
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err == nil {
+		timeout = time.Duration(ms) * time.Millisecond
+	} else {
+		timeout, err = time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("must be milliseconds or a duration, got %q", value)
+		}
+	}
+
+	if timeout <= 0 {
+		return 0, fmt.Errorf("must be positive, got %q", value)
+	}
+
+	return timeout, nil
+}
+
+// routeKey identifies the matched route as in the timeouts config, e.g.
+// "post /ping/batch". Config keys are lower case once loaded. The version
+// prefix is dropped, every version of a route shares its settings.
+func routeKey(c *fiber.Ctx) string {
+	return strings.ToLower(c.Method() + " " + unversionedPath(routePath(c)))
+}
+
+// routePath is the path of the matched route as spans, logs and metrics name
+// it. Fiber gives the routes of a group with a trailing slash, "/v1/ping/" is
+// "/v1/ping".
+func routePath(c *fiber.Ctx) string {
+	path := c.Route().Path
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+
+	return path
+}
@@ -0,0 +1,267 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"service-a/util/auth"
+	"service-a/util/config"
+	"service-a/util/idempotency"
+	"service-a/util/logging"
+	"service-a/util/wait"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// HeaderIdempotencyKey identifies a request across client retries
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed marks a response replayed from the store
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+
+	// idempotencyPollInterval is how often a concurrent duplicate checks
+	// whether the first request completed
+	idempotencyPollInterval = 50 * time.Millisecond
+)
+
+// Idempotency replays the stored response of a request sent again with the
+// same Idempotency-Key, instead of running it twice. Keys are scoped to the
+// client and route, and bound to a fingerprint of the request.
+type Idempotency struct {
+	logger *logrus.Logger
+	store  idempotency.Store
+
+	mu     sync.RWMutex
+	config config.Idempotency
+}
+
+// NewIdempotency creates the idempotency of the idempotency config, keeping
+// the records in store
+func NewIdempotency(config config.Idempotency, store idempotency.Store, logger *logrus.Logger) *Idempotency {
+	return &Idempotency{
+		logger: logger,
+		store:  store,
+
+		config: config,
+	}
+}
+
+// SetConfig replaces the idempotency config, applied to the following requests
+func (idem *Idempotency) SetConfig(config config.Idempotency) {
+	idem.mu.Lock()
+	defer idem.mu.Unlock()
+
+	idem.config = config
+}
+
+// Handler creates a middleware for the routes that must not run twice for
+// one Idempotency-Key. Requests without the header are passed on.
+//
+// The first request claims the key and its response is stored, unless it
+// never completed: a server error, a rate limited or cancelled request may be
+// retried and releases the key. A duplicate of a completed request
+// gets the stored response with the Idempotent-Replayed header; a duplicate
+// of a request still in progress waits for it, or gets a 409 when
+// idempotency.conflict is "reject" or the wait times out. A key reused for a
+// different request gets a 422.
+func (idem *Idempotency) Handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		const op = "middleware.Idempotency.Handler"
+
+		idem.mu.RLock()
+		config := idem.config
+		idem.mu.RUnlock()
+
+		key := c.Get(HeaderIdempotencyKey)
+		if !config.Enabled || key == "" {
+			return c.Next()
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			return fiber.NewError(fiber.StatusBadRequest,
+				fmt.Sprintf("%s must be at most %d characters", HeaderIdempotencyKey, maxIdempotencyKeyLength))
+		}
+
+		ctx := c.UserContext()
+		span := trace.SpanFromContext(ctx)
+		sc := span.SpanContext()
+
+		span.SetAttributes(attribute.String("idempotency.key", key))
+
+		// Versions answer with different bodies, each keeps its own responses
+		storeKey := idempotencyScope(c) + "|" + c.Method() + " " + c.Route().Path + "|" + key
+		claim := idempotency.Record{
+			Fingerprint: fingerprint(c),
+			TraceID:     sc.TraceID().String(),
+			SpanID:      sc.SpanID().String(),
+		}
+
+		record, claimed, err := idem.store.Claim(ctx, storeKey, claim, config.InProgressTTL)
+		if err != nil {
+			// Fail open, an unavailable store must not take the service down
+			logging.LogWithTrace(ctx, idem.logger).WithFields(logrus.Fields{
+				"[op]":  op,
+				"error": err.Error(),
+			}).Warn("idempotency store failed, request let through")
+
+			return c.Next()
+		}
+
+		if claimed {
+			return idem.run(c, storeKey, claim, config)
+		}
+
+		if record.Fingerprint != claim.Fingerprint {
+			return fiber.NewError(fiber.StatusUnprocessableEntity,
+				fmt.Sprintf("%s was already used for a different request", HeaderIdempotencyKey))
+		}
+
+		if !record.Completed {
+			if config.Conflict == "reject" {
+				return idem.conflict(c)
+			}
+
+			span.AddEvent("idempotency.waiting")
+
+			record, err = idem.wait(ctx, storeKey, config.WaitTimeout)
+			if err != nil || record == nil || !record.Completed {
+				return idem.conflict(c)
+			}
+		}
+
+		return idem.replay(c, key, record)
+	}
+}
+
+// run forwards the request that claimed the key and stores its response
+func (idem *Idempotency) run(c *fiber.Ctx, storeKey string, record idempotency.Record, config config.Idempotency) error {
+	const op = "middleware.Idempotency.run"
+
+	// The response must be final before it is stored
+	if err := c.Next(); err != nil {
+		if serr := SendProblem(c, ProblemFromError(c, err)); serr != nil {
+			return serr
+		}
+	}
+
+	// Storing is done with the request context gone, a failure is only logged
+	cancelled := c.UserContext().Err() != nil
+	ctx := context.WithoutCancel(c.UserContext())
+	status := c.Response().StatusCode()
+
+	var err error
+	if cancelled || retryable(status) {
+		err = idem.store.Release(ctx, storeKey)
+	} else {
+		record.Completed = true
+		record.Status = status
+		record.ContentType = string(c.Response().Header.ContentType())
+		record.Body = bytes.Clone(c.Response().Body())
+
+		err = idem.store.Complete(ctx, storeKey, record, config.TTL)
+	}
+
+	if err != nil {
+		logging.LogWithTrace(ctx, idem.logger).WithFields(logrus.Fields{
+			"[op]":  op,
+			"error": err.Error(),
+		}).Warn("idempotency store failed, response not recorded")
+	}
+
+	return nil
+}
+
+// retryable reports whether a response status tells the client to send the
+// request again, so it must not be replayed: server errors, rate limiting and
+// cancellation
+func retryable(status int) bool {
+	return status >= fiber.StatusInternalServerError ||
+		status == fiber.StatusTooManyRequests ||
+		status == 499
+}
+
+// wait polls the record of a request in progress until it completes, is
+// released, or timeout elapses
+func (idem *Idempotency) wait(ctx context.Context, storeKey string, timeout time.Duration) (*idempotency.Record, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		if err := wait.Sleep(ctx, idempotencyPollInterval); err != nil {
+			return nil, err
+		}
+
+		record, err := idem.store.Get(ctx, storeKey)
+		if err != nil || record == nil || record.Completed {
+			return record, err
+		}
+	}
+}
+
+// replay sends the stored response, linked to the trace that produced it
+func (idem *Idempotency) replay(c *fiber.Ctx, key string, record *idempotency.Record) error {
+	span := trace.SpanFromContext(c.UserContext())
+
+	span.AddEvent("idempotency.replayed", trace.WithAttributes(
+		attribute.String("idempotency.key", key),
+		attribute.String("idempotency.original_trace_id", record.TraceID),
+		attribute.Int("idempotency.original_status", record.Status),
+	))
+	span.SetAttributes(attribute.Bool("idempotency.replayed", true))
+
+	traceID, terr := trace.TraceIDFromHex(record.TraceID)
+	spanID, serr := trace.SpanIDFromHex(record.SpanID)
+	if terr == nil && serr == nil {
+		span.AddLink(trace.Link{
+			SpanContext: trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID:    traceID,
+				SpanID:     spanID,
+				TraceFlags: trace.FlagsSampled,
+				Remote:     true,
+			}),
+			Attributes: []attribute.KeyValue{attribute.String("link.type", "idempotency.original")},
+		})
+	}
+
+	c.Set(HeaderIdempotentReplayed, "true")
+	c.Set(fiber.HeaderContentType, record.ContentType)
+
+	return c.Status(record.Status).Send(record.Body)
+}
+
+func (idem *Idempotency) conflict(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderRetryAfter, "1")
+
+	return fiber.NewError(fiber.StatusConflict,
+		fmt.Sprintf("A request with this %s is still in progress, retry later", HeaderIdempotencyKey))
+}
+
+// idempotencyScope identifies the client, authenticated or not, so that two
+// clients picking the same key do not share a response
+func idempotencyScope(c *fiber.Ctx) string {
+	if principal, ok := auth.PrincipalFromContext(c.UserContext()); ok {
+		return principal.Method + ":" + principal.Subject
+	}
+
+	return "ip:" + c.IP()
+}
+
+// fingerprint hashes what makes two requests the same: query and body
+func fingerprint(c *fiber.Ctx) string {
+	h := sha256.New()
+	h.Write(c.Request().URI().QueryString())
+	h.Write([]byte{0})
+	h.Write(c.Body())
+
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"service-a/util/config"
+	"service-a/util/idempotency"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+func TestIdempotencyReleasesUnfinishedRequests(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  func(c *fiber.Ctx, cancel context.CancelFunc) error
+		status   int
+		replayed bool // whether the retry gets the first response back
+	}{
+		{
+			name: "a completed request is replayed",
+			handler: func(c *fiber.Ctx, _ context.CancelFunc) error {
+				return c.SendString("pong")
+			},
+			status:   fiber.StatusOK,
+			replayed: true,
+		},
+		{
+			name: "a client error is replayed",
+			handler: func(c *fiber.Ctx, _ context.CancelFunc) error {
+				return fiber.NewError(fiber.StatusBadRequest, "bad")
+			},
+			status:   fiber.StatusBadRequest,
+			replayed: true,
+		},
+		{
+			name: "a cancelled request runs again",
+			handler: func(c *fiber.Ctx, cancel context.CancelFunc) error {
+				cancel()
+
+				return c.UserContext().Err()
+			},
+			status: 499,
+		},
+		{
+			name: "a request cancelled after its response was written runs again",
+			handler: func(c *fiber.Ctx, cancel context.CancelFunc) error {
+				cancel()
+
+				return c.SendString("pong")
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name: "a rate limited request runs again",
+			handler: func(c *fiber.Ctx, _ context.CancelFunc) error {
+				return fiber.NewError(fiber.StatusTooManyRequests, "slow down")
+			},
+			status: fiber.StatusTooManyRequests,
+		},
+		{
+			name: "a server error runs again",
+			handler: func(c *fiber.Ctx, _ context.CancelFunc) error {
+				return fiber.ErrInternalServerError
+			},
+			status: fiber.StatusInternalServerError,
+		},
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := idempotency.NewMemoryStore(10, 1024)
+			idem := NewIdempotency(config.Idempotency{
+				Enabled:       true,
+				TTL:           time.Minute,
+				InProgressTTL: time.Minute,
+				Conflict:      "reject",
+			}, store, logger)
+
+			runs := 0
+			app := fiber.New()
+			app.Post("/ping", func(c *fiber.Ctx) error {
+				// Stands in for the client going away mid-request
+				ctx, cancel := context.WithCancel(c.UserContext())
+				defer cancel()
+
+				c.SetUserContext(ctx)
+				c.Locals("cancel", cancel)
+
+				return c.Next()
+			}, idem.Handler(), func(c *fiber.Ctx) error {
+				runs++
+				if runs > 1 {
+					return c.SendString("retried")
+				}
+
+				return tt.handler(c, c.Locals("cancel").(context.CancelFunc))
+			})
+
+			for i := 0; i < 2; i++ {
+				req := httptest.NewRequest(fiber.MethodPost, "/ping", nil)
+				req.Header.Set(HeaderIdempotencyKey, "key")
+
+				resp, err := app.Test(req)
+				if err != nil {
+					t.Fatalf("request %d: %v", i, err)
+				}
+
+				if i == 0 && resp.StatusCode != tt.status {
+					t.Fatalf("first status = %d, want %d", resp.StatusCode, tt.status)
+				}
+			}
+
+			want := 2
+			if tt.replayed {
+				want = 1
+			}
+
+			if runs != want {
+				t.Fatalf("handler ran %d times, want %d", runs, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"service-a/util/config"
+	"service-a/util/logging"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestValidator checks requests against the OpenAPI document: query
+// parameters, headers and bodies. Routes missing from the document are
+// passed on unchecked.
+type RequestValidator struct {
+	logger  *logrus.Logger
+	router  routers.Router
+	options *openapi3filter.Options
+
+	mu     sync.RWMutex
+	config config.OpenAPI
+}
+
+// NewRequestValidator creates a validator of the requests described by document
+func NewRequestValidator(config config.OpenAPI, document *openapi3.T, logger *logrus.Logger) (*RequestValidator, error) {
+	router, err := legacy.NewRouter(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create openapi router: %w", err)
+	}
+
+	options := &openapi3filter.Options{
+		// Credentials are checked by the Auth middleware
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+
+	// Keep the error short, the default one dumps the whole schema
+	options.WithCustomSchemaErrorFunc(func(err *openapi3.SchemaError) string {
+		if pointer := err.JSONPointer(); len(pointer) > 0 {
+			return fmt.Sprintf("%s: %s", strings.Join(pointer, "."), err.Reason)
+		}
+
+		return err.Reason
+	})
+
+	return &RequestValidator{
+		logger:  logger,
+		router:  router,
+		options: options,
+
+		config: config,
+	}, nil
+}
+
+// SetConfig replaces the openapi config, applied to the following requests
+func (validator *RequestValidator) SetConfig(config config.OpenAPI) {
+	validator.mu.Lock()
+	defer validator.mu.Unlock()
+
+	validator.config = config
+}
+
+func (validator *RequestValidator) enabled() bool {
+	validator.mu.RLock()
+	defer validator.mu.RUnlock()
+
+	return validator.config.Validate
+}
+
+// Handler creates a middleware rejecting the requests that do not match the
+// document with a 400, or a 415 when a body is not JSON
+func (validator *RequestValidator) Handler() fiber.Handler {
+	const op = "middleware.RequestValidator"
+
+	return func(c *fiber.Ctx) error {
+		if !validator.enabled() {
+			return c.Next()
+		}
+
+		req, err := adaptor.ConvertRequest(c, false)
+		if err != nil {
+			return err
+		}
+
+		// Routes are matched without their trailing slash, like routeKey
+		if len(req.URL.Path) > 1 {
+			req.URL.Path = strings.TrimSuffix(req.URL.Path, "/")
+		}
+
+		route, pathParams, err := validator.router.FindRoute(req)
+		if err != nil {
+			return c.Next()
+		}
+
+		err = openapi3filter.ValidateRequest(c.UserContext(), &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    validator.options,
+		})
+		if err == nil {
+			return c.Next()
+		}
+
+		ctx := c.UserContext()
+
+		trace.SpanFromContext(ctx).AddEvent("request_invalid", trace.WithAttributes(
+			attribute.String("openapi.operation_id", route.Operation.OperationID),
+			attribute.String("openapi.error", err.Error()),
+		))
+
+		logging.LogWithTrace(ctx, validator.logger).WithFields(logrus.Fields{
+			"[op]":      op,
+			"operation": route.Operation.OperationID,
+			"err":       err.Error(),
+		}).Debug("request does not match the openapi document")
+
+		status := fiber.StatusBadRequest
+
+		var requestErr *openapi3filter.RequestError
+		if errors.As(err, &requestErr) && requestErr.RequestBody != nil && !c.Is("json") {
+			status = fiber.StatusUnsupportedMediaType
+		}
+
+		return fiber.NewError(status, err.Error())
+	}
+}
@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MIMEProblemJSON is the media type of RFC 7807 problem details
+const MIMEProblemJSON = "application/problem+json"
+
+// Problem is an RFC 7807 problem details body, extended with the identifiers
+// of the request's trace
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	TraceID  string `json:"trace_id,omitempty"`
+	TraceURL string `json:"trace_url,omitempty"`
+}
+
+// grpcStatuses translates the gRPC status codes returned by upstream services
+// to the HTTP status the client gets
+var grpcStatuses = map[codes.Code]int{
+	codes.OK:                 fiber.StatusOK,
+	codes.Canceled:           499, // Client Closed Request
+	codes.Unknown:            fiber.StatusInternalServerError,
+	codes.InvalidArgument:    fiber.StatusBadRequest,
+	codes.DeadlineExceeded:   fiber.StatusGatewayTimeout,
+	codes.NotFound:           fiber.StatusNotFound,
+	codes.AlreadyExists:      fiber.StatusConflict,
+	codes.PermissionDenied:   fiber.StatusForbidden,
+	codes.ResourceExhausted:  fiber.StatusTooManyRequests,
+	codes.FailedPrecondition: fiber.StatusBadRequest,
+	codes.Aborted:            fiber.StatusConflict,
+	codes.OutOfRange:         fiber.StatusBadRequest,
+	codes.Unimplemented:      fiber.StatusNotImplemented,
+	codes.Internal:           fiber.StatusInternalServerError,
+	codes.Unavailable:        fiber.StatusServiceUnavailable,
+	codes.DataLoss:           fiber.StatusInternalServerError,
+	codes.Unauthenticated:    fiber.StatusUnauthorized,
+}
+
+// NewProblem builds the problem details of the current request
+func NewProblem(c *fiber.Ctx, status int, detail string) Problem {
+	problem := Problem{
+		Type:     "about:blank",
+		Title:    statusTitle(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: strings.Clone(c.OriginalURL()), // May outlive the request in streamed responses
+	}
+
+	sc := trace.SpanContextFromContext(c.UserContext())
+	if sc.IsValid() {
+		problem.TraceID = sc.TraceID().String()
+	}
+
+	if url, ok := c.Locals(traceURLLocal).(string); ok {
+		problem.TraceURL = url
+	}
+
+	return problem
+}
+
+// ProblemFromError builds the problem details describing err
+func ProblemFromError(c *fiber.Ctx, err error) Problem {
+	return NewProblem(c, fiber.StatusInternalServerError, "").WithError(err)
+}
+
+// WithError returns a copy of problem describing err.
+//
+// Fiber errors keep their status and message. Errors carrying a gRPC status
+// are translated with grpcStatuses; their message is only shown for client
+// errors, server side failures get a generic detail so that internal details
+// do not leak. An expired or cancelled request context gives 504 or 499. Any
+// other error is an internal server error.
+func (problem Problem) WithError(err error) Problem {
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return problem.withStatus(fiberErr.Code, fiberErr.Message)
+	}
+
+	// The request ran out of time or was abandoned before reaching service-b
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return problem.withStatus(fiber.StatusGatewayTimeout, "The request did not complete within its deadline")
+	case errors.Is(err, context.Canceled):
+		return problem.withStatus(499, "The request was cancelled")
+	}
+
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcErr) {
+		st := grpcErr.GRPCStatus()
+
+		code, ok := grpcStatuses[st.Code()]
+		if !ok {
+			code = fiber.StatusInternalServerError
+		}
+
+		switch {
+		case code < fiber.StatusInternalServerError:
+			return problem.withStatus(code, st.Message())
+		case st.Code() == codes.Unavailable:
+			return problem.withStatus(code, "An upstream service is unavailable, retry later")
+		case st.Code() == codes.DeadlineExceeded:
+			return problem.withStatus(code, "An upstream service did not answer in time")
+		}
+
+		return problem.withStatus(code, "An upstream service failed to handle the request")
+	}
+
+	return problem.withStatus(fiber.StatusInternalServerError, "Internal server error")
+}
+
+func (problem Problem) withStatus(status int, detail string) Problem {
+	problem.Status = status
+	problem.Title = statusTitle(status)
+	problem.Detail = detail
+
+	return problem
+}
+
+func statusTitle(status int) string {
+	if title := utils.StatusMessage(status); title != "" {
+		return title
+	}
+
+	return "Client Closed Request"
+}
+
+// SendProblem writes problem as an application/problem+json response
+func SendProblem(c *fiber.Ctx, problem Problem) error {
+	return c.Status(problem.Status).JSON(problem, MIMEProblemJSON)
+}
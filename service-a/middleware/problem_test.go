@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestProblemFromError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		detail string
+	}{
+		{name: "fiber error", err: fiber.NewError(fiber.StatusNotFound, "no such ping"), status: fiber.StatusNotFound, detail: "no such ping"},
+		{name: "context canceled", err: context.Canceled, status: 499, detail: "The request was cancelled"},
+		{name: "context deadline exceeded", err: fmt.Errorf("calling service-b: %w", context.DeadlineExceeded), status: fiber.StatusGatewayTimeout, detail: "The request did not complete within its deadline"},
+		{name: "plain error", err: errors.New("db password is hunter2"), status: fiber.StatusInternalServerError, detail: "Internal server error"},
+
+		// Client errors keep the message of service-b
+		{name: "grpc Canceled", err: status.Error(codes.Canceled, "canceled"), status: 499, detail: "canceled"},
+		{name: "grpc InvalidArgument", err: status.Error(codes.InvalidArgument, "empty message"), status: fiber.StatusBadRequest, detail: "empty message"},
+		{name: "grpc NotFound", err: status.Error(codes.NotFound, "no ping"), status: fiber.StatusNotFound, detail: "no ping"},
+		{name: "grpc AlreadyExists", err: status.Error(codes.AlreadyExists, "exists"), status: fiber.StatusConflict, detail: "exists"},
+		{name: "grpc PermissionDenied", err: status.Error(codes.PermissionDenied, "denied"), status: fiber.StatusForbidden, detail: "denied"},
+		{name: "grpc ResourceExhausted", err: status.Error(codes.ResourceExhausted, "slow down"), status: fiber.StatusTooManyRequests, detail: "slow down"},
+		{name: "grpc FailedPrecondition", err: status.Error(codes.FailedPrecondition, "not ready"), status: fiber.StatusBadRequest, detail: "not ready"},
+		{name: "grpc Aborted", err: status.Error(codes.Aborted, "aborted"), status: fiber.StatusConflict, detail: "aborted"},
+		{name: "grpc OutOfRange", err: status.Error(codes.OutOfRange, "too far"), status: fiber.StatusBadRequest, detail: "too far"},
+		{name: "grpc Unauthenticated", err: status.Error(codes.Unauthenticated, "no token"), status: fiber.StatusUnauthorized, detail: "no token"},
+
+		// Server errors never show the message of service-b
+		{name: "grpc Unknown", err: status.Error(codes.Unknown, "stack trace"), status: fiber.StatusInternalServerError, detail: "An upstream service failed to handle the request"},
+		{name: "grpc DeadlineExceeded", err: status.Error(codes.DeadlineExceeded, "slow query"), status: fiber.StatusGatewayTimeout, detail: "An upstream service did not answer in time"},
+		{name: "grpc Unimplemented", err: status.Error(codes.Unimplemented, "method x"), status: fiber.StatusNotImplemented, detail: "An upstream service failed to handle the request"},
+		{name: "grpc Internal", err: status.Error(codes.Internal, "nil pointer in store.go"), status: fiber.StatusInternalServerError, detail: "An upstream service failed to handle the request"},
+		{name: "grpc Unavailable", err: status.Error(codes.Unavailable, "dial tcp 10.0.0.7"), status: fiber.StatusServiceUnavailable, detail: "An upstream service is unavailable, retry later"},
+		{name: "grpc DataLoss", err: status.Error(codes.DataLoss, "corrupt row"), status: fiber.StatusInternalServerError, detail: "An upstream service failed to handle the request"},
+		{name: "grpc unknown code", err: status.Error(codes.Code(42), "what"), status: fiber.StatusInternalServerError, detail: "An upstream service failed to handle the request"},
+		{name: "wrapped grpc error", err: fmt.Errorf("error sending request: %w", status.Error(codes.NotFound, "no ping")), status: fiber.StatusNotFound, detail: "no ping"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/ping", func(c *fiber.Ctx) error {
+				return SendProblem(c, ProblemFromError(c, tt.err))
+			})
+
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/ping?x=1", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := resp.Header.Get(fiber.HeaderContentType); got != MIMEProblemJSON {
+				t.Fatalf("Content-Type = %q, want %q", got, MIMEProblemJSON)
+			}
+
+			var problem Problem
+			if err := json.NewDecoder(resp.Body).Decode(&problem); err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.StatusCode != tt.status || problem.Status != tt.status || problem.Detail != tt.detail {
+				t.Fatalf("got %d %+v, want %d %q", resp.StatusCode, problem, tt.status, tt.detail)
+			}
+
+			if problem.Title == "" || problem.Type != "about:blank" || problem.Instance != "/ping?x=1" {
+				t.Fatalf("problem = %+v, want a title, about:blank and the request as instance", problem)
+			}
+		})
+	}
+}
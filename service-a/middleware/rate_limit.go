@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"service-a/util/auth"
+	"service-a/util/config"
+	"service-a/util/logging"
+	"service-a/util/ratelimit"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// HeaderAPIKey carries the API key of an authenticated client
+	HeaderAPIKey = "X-Api-Key"
+
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+	HeaderRateLimitPolicy    = "RateLimit-Policy"
+)
+
+// RateLimiter limits the requests of every client with a token bucket per
+// client and route, kept in a pluggable ratelimit.Store
+type RateLimiter struct {
+	logger *logrus.Logger
+	store  ratelimit.Store
+
+	requests metric.Int64Counter
+
+	mu     sync.RWMutex
+	config config.RateLimit
+}
+
+// NewRateLimiter creates a rate limiter for the rate_limit config
+func NewRateLimiter(config config.RateLimit, store ratelimit.Store, logger *logrus.Logger, meter metric.Meter) (*RateLimiter, error) {
+	requests, err := meter.Int64Counter("http.server.rate_limit.requests",
+		metric.WithDescription("Requests checked by the rate limiter, by route and outcome"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rate limit counter: %w", err)
+	}
+
+	return &RateLimiter{
+		logger: logger,
+		store:  store,
+
+		requests: requests,
+
+		config: config,
+	}, nil
+}
+
+// SetConfig replaces the rate_limit config, applied to the following requests
+func (limiter *RateLimiter) SetConfig(config config.RateLimit) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	limiter.config = config
+}
+
+// Handler creates a middleware enforcing the limit of the matched route. It
+// must be registered on the route itself, not with Use, to see the route.
+func (limiter *RateLimiter) Handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		const op = "middleware.RateLimiter.Handler"
+
+		route := routeKey(c)
+
+		limit, keyType, client, enabled := limiter.rule(c, route)
+		if !enabled {
+			return c.Next()
+		}
+
+		ctx := c.UserContext()
+		span := trace.SpanFromContext(ctx)
+
+		result, err := limiter.store.Take(ctx, route+"|"+client, limit, time.Now())
+		if err != nil {
+			// Fail open, an unavailable store must not take the service down
+			logging.LogWithTrace(ctx, limiter.logger).WithFields(logrus.Fields{
+				"[op]":  op,
+				"route": route,
+				"error": err.Error(),
+			}).Warn("rate limit store failed, request let through")
+
+			return c.Next()
+		}
+
+		c.Set(HeaderRateLimitLimit, strconv.Itoa(limit.Burst))
+		c.Set(HeaderRateLimitRemaining, strconv.Itoa(result.Remaining))
+		c.Set(HeaderRateLimitReset, strconv.Itoa(ceilSeconds(result.Reset)))
+		c.Set(HeaderRateLimitPolicy, fmt.Sprintf("%d;w=%d", limit.Burst, ceilSeconds(limit.Window())))
+
+		outcome := "allowed"
+		if !result.Allowed {
+			outcome = "throttled"
+		}
+
+		limiter.requests.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("http.route", routePath(c)),
+			attribute.String("ratelimit.outcome", outcome),
+		))
+
+		span.SetAttributes(
+			attribute.String("ratelimit.key_type", keyType),
+			attribute.Int("ratelimit.limit", limit.Burst),
+			attribute.Int("ratelimit.remaining", result.Remaining),
+			attribute.Bool("ratelimit.throttled", !result.Allowed),
+		)
+
+		if !result.Allowed {
+			span.AddEvent("rate_limited", trace.WithAttributes(
+				attribute.Int64("ratelimit.retry_after_ms", result.RetryAfter.Milliseconds()),
+			))
+
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(ceilSeconds(result.RetryAfter)))
+
+			return fiber.NewError(fiber.StatusTooManyRequests,
+				fmt.Sprintf("Rate limit of %d requests exceeded, retry in %ds", limit.Burst, ceilSeconds(result.RetryAfter)))
+		}
+
+		// Forward to next handler
+		return c.Next()
+	}
+}
+
+// rule returns the limit of route and the client the request comes from
+func (limiter *RateLimiter) rule(c *fiber.Ctx, route string) (limit ratelimit.Limit, keyType, client string, enabled bool) {
+	limiter.mu.RLock()
+	defer limiter.mu.RUnlock()
+
+	if !limiter.config.Enabled {
+		return limit, "", "", false
+	}
+
+	limit = ratelimit.Limit{Rate: limiter.config.Rate, Burst: limiter.config.Burst}
+	if rule, ok := limiter.config.Routes[route]; ok {
+		limit = ratelimit.Limit{Rate: rule.Rate, Burst: rule.Burst}
+	}
+
+	if limit.Burst <= 0 {
+		return limit, "", "", false
+	}
+
+	// Requests without the configured key are limited by client IP
+	keyType, client = "ip", c.IP()
+
+	switch limiter.config.KeyBy {
+	case "api_key":
+		// Only a verified caller gets its own bucket, a made up key would not
+		if principal, ok := auth.PrincipalFromContext(c.UserContext()); ok {
+			keyType, client = "principal", principal.Method+":"+principal.Subject
+		}
+	case "header":
+		if limiter.config.Header != "" {
+			if value := c.Get(limiter.config.Header); value != "" {
+				keyType, client = "header", value
+			}
+		}
+	}
+
+	return limit, keyType, keyType + ":" + client, true
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
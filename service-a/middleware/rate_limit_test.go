@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"service-a/util/auth"
+	"service-a/util/config"
+	"service-a/util/ratelimit"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+func TestRateLimiterKeysByPrincipal(t *testing.T) {
+	type request struct {
+		subject string // authenticated principal, none when empty
+		apiKey  string
+		status  int
+	}
+
+	tests := []struct {
+		name     string
+		requests []request
+	}{
+		{
+			name: "principals get a bucket each",
+			requests: []request{
+				{subject: "alice", status: fiber.StatusOK},
+				{subject: "bob", status: fiber.StatusOK},
+				{subject: "alice", status: fiber.StatusTooManyRequests},
+			},
+		},
+		{
+			name: "unverified API keys share the bucket of the IP",
+			requests: []request{
+				{apiKey: "made-up-1", status: fiber.StatusOK},
+				{apiKey: "made-up-2", status: fiber.StatusTooManyRequests},
+			},
+		},
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter, err := NewRateLimiter(config.RateLimit{Enabled: true, KeyBy: "api_key", Rate: 0.001, Burst: 1},
+				ratelimit.NewMemoryStore(16), logger, noop.NewMeterProvider().Meter("test"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			app := fiber.New()
+			app.Get("/ping", func(c *fiber.Ctx) error {
+				// Stands in for the authentication middleware
+				if subject := c.Get("X-Subject"); subject != "" {
+					c.SetUserContext(auth.WithPrincipal(c.UserContext(),
+						auth.Principal{Subject: subject, Method: auth.MethodAPIKey}))
+				}
+
+				return c.Next()
+			}, limiter.Handler(), func(c *fiber.Ctx) error {
+				return c.SendString("pong")
+			})
+
+			for i, r := range tt.requests {
+				req := httptest.NewRequest(fiber.MethodGet, "/ping", nil)
+				if r.subject != "" {
+					req.Header.Set("X-Subject", r.subject)
+				}
+				if r.apiKey != "" {
+					req.Header.Set(HeaderAPIKey, r.apiKey)
+				}
+
+				resp, err := app.Test(req)
+				if err != nil {
+					t.Fatalf("request %d: %v", i, err)
+				}
+
+				if resp.StatusCode != r.status {
+					t.Fatalf("request %d = %d, want %d", i, resp.StatusCode, r.status)
+				}
+			}
+		})
+	}
+}
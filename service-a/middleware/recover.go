@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"fmt"
+
+	"service-a/util/tracing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Recover creates a middleware that turns a panic anywhere in the chain,
+// other middlewares included, into a 500 problem response. It must be
+// registered first. The panic is recorded on the request span, which the
+// Tracing middleware leaves open when unwinding, and logged with its stack;
+// the panic value never reaches the client.
+func Recover(logger *logrus.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) (err error) {
+		defer func() {
+			value := recover()
+			if value == nil {
+				return
+			}
+
+			ctx := c.UserContext()
+			tracing.RecordPanic(ctx, logger, value)
+
+			err = SendProblem(c, NewProblem(c, fiber.StatusInternalServerError, "Internal server error"))
+
+			// Finish the span the way Tracing would have, RecordPanic already
+			// marked it as failed
+			status := c.Response().StatusCode()
+
+			route := routePath(c)
+
+			span := trace.SpanFromContext(ctx)
+			span.SetName(fmt.Sprintf("%s %s", c.Method(), route))
+			span.SetAttributes(
+				semconv.HTTPRoute(route),
+				semconv.HTTPResponseStatusCode(status),
+			)
+			span.End()
+		}()
+
+		// Forward to next handler
+		return c.Next()
+	}
+}
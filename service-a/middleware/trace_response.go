@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+
+	"service-a/util/config"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// HeaderTraceResponse is the W3C Trace Context Level 2 response header
+	HeaderTraceResponse = "traceresponse"
+
+	// HeaderTraceURL carries the link to the trace rendered from the URL template
+	HeaderTraceURL = "X-Trace-Url"
+
+	traceURLLocal = "trace_url"
+)
+
+// TraceResponse creates a middleware that returns the trace of the request to
+// the client, so that a reported problem can be matched with its trace.
+// It must run after Tracing.
+func TraceResponse(config config.TraceResponse) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		sc := trace.SpanContextFromContext(c.UserContext())
+		if !sc.IsValid() {
+			return c.Next()
+		}
+
+		if config.Traceresponse {
+			c.Set(HeaderTraceResponse, fmt.Sprintf("00-%s-%s-%s", sc.TraceID(), sc.SpanID(), sc.TraceFlags()))
+		}
+
+		if config.TraceIDHeader != "" {
+			c.Set(config.TraceIDHeader, sc.TraceID().String())
+		}
+
+		if config.URLTemplate != "" {
+			url := strings.NewReplacer(
+				"{trace_id}", sc.TraceID().String(),
+				"{span_id}", sc.SpanID().String(),
+			).Replace(config.URLTemplate)
+
+			c.Set(HeaderTraceURL, url)
+			c.Locals(traceURLLocal, url)
+		}
+
+		// Forward to next handler
+		return c.Next()
+	}
+}
@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing creates a middleware that starts a SERVER span for every request.
+//
+// The trace context sent by the caller (traceparent, baggage, ...) is
+// extracted with the global propagator so the request joins the caller's
+// trace. The span context is stored in c.UserContext() for the handlers.
+func Tracing(tracer trace.Tracer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), requestCarrier{c})
+
+		// The route is only known once the router matched it, the span is
+		// renamed afterwards
+		ctx, span := tracer.Start(ctx, c.Method(),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Method()),
+				semconv.URLPath(c.Path()),
+				semconv.URLScheme(c.Protocol()),
+				semconv.ServerAddress(c.Hostname()),
+				semconv.ClientAddress(c.IP()),
+				semconv.UserAgentOriginal(c.Get(fiber.HeaderUserAgent)),
+				semconv.NetworkProtocolVersion(strings.TrimPrefix(string(c.Request().Header.Protocol()), "HTTP/")),
+			),
+		)
+
+		// The span is ended on return; a panic leaves it open for Recover,
+		// which records the panic on it before ending it
+		c.SetUserContext(ctx)
+
+		// Forward to next handler
+		err := c.Next()
+
+		// Let the error handler decide the status before it is recorded
+		if err != nil {
+			span.RecordError(err)
+
+			if herr := c.App().ErrorHandler(c, err); herr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		route := routePath(c)
+		status := c.Response().StatusCode()
+
+		span.SetName(fmt.Sprintf("%s %s", c.Method(), route))
+		span.SetAttributes(
+			semconv.HTTPRoute(route),
+			semconv.HTTPResponseStatusCode(status),
+		)
+
+		if size := bodySize(c); size >= 0 {
+			span.SetAttributes(semconv.HTTPResponseBodySize(size))
+		}
+
+		// Only server errors mark a SERVER span as failed
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, fmt.Sprintf("%d %s", status, utils.StatusMessage(status)))
+		}
+
+		span.End()
+
+		return nil
+	}
+}
+
+// bodySize returns the size of the response body, or -1 for a streamed body
+// whose size is unknown until it was written. Reading a streamed body would
+// consume the stream.
+func bodySize(c *fiber.Ctx) int {
+	if c.Response().IsBodyStream() {
+		return -1
+	}
+
+	return len(c.Response().Body())
+}
+
+// requestCarrier adapts the request headers to propagation.TextMapCarrier
+type requestCarrier struct {
+	c *fiber.Ctx
+}
+
+func (rc requestCarrier) Get(key string) string {
+	return rc.c.Get(key)
+}
+
+func (rc requestCarrier) Set(key, value string) {
+	rc.c.Request().Header.Set(key, value)
+}
+
+func (rc requestCarrier) Keys() []string {
+	keys := make([]string, 0)
+	rc.c.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+
+	return keys
+}
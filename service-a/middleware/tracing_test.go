@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+func TestSpanNamedAfterRoute(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	tests := []struct {
+		name  string
+		path  string
+		panic bool
+		route string
+	}{
+		{name: "group root", path: "/v1/ping", route: "/v1/ping"},
+		{name: "group root with slash", path: "/v1/ping/", route: "/v1/ping"},
+		{name: "nested route", path: "/v1/ping/stream", route: "/v1/ping/stream"},
+		{name: "root", path: "/", route: "/"},
+		{name: "panicking handler", path: "/v1/ping", panic: true, route: "/v1/ping"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+			handler := func(c *fiber.Ctx) error {
+				if tt.panic {
+					panic("boom")
+				}
+
+				return c.SendString("pong")
+			}
+
+			app := fiber.New()
+			app.Use(Recover(logger), Tracing(tracer), AccessLog(logger))
+			app.Get("/", handler)
+			ping := app.Group("/v1/ping")
+			ping.Get("/", handler)
+			ping.Get("/stream", handler)
+
+			if _, err := app.Test(httptest.NewRequest(fiber.MethodGet, tt.path, nil)); err != nil {
+				t.Fatal(err)
+			}
+
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("%d spans ended, want 1", len(spans))
+			}
+
+			if want := "GET " + tt.route; spans[0].Name() != want {
+				t.Fatalf("span name = %q, want %q", spans[0].Name(), want)
+			}
+
+			for _, attr := range spans[0].Attributes() {
+				if attr.Key == semconv.HTTPRouteKey && attr.Value.AsString() != tt.route {
+					t.Fatalf("http.route = %q, want %q", attr.Value.AsString(), tt.route)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+
+	"service-a/util/config"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// HeaderDeprecation tells that a route is deprecated since the given date (RFC 9745)
+	HeaderDeprecation = "Deprecation"
+	// HeaderSunset tells when a deprecated route stops responding (RFC 8594)
+	HeaderSunset = "Sunset"
+
+	versionLocal = "api.version"
+)
+
+var versionPrefix = regexp.MustCompile(`^/v[0-9]+(/|$)`)
+
+// Versions records the API version used by every request, and flags the
+// unversioned routes as deprecated aliases of a versioned group
+type Versions struct {
+	requests metric.Int64Counter
+
+	mu     sync.RWMutex
+	config config.Versioning
+}
+
+// NewVersions creates the API versions of the versioning config
+func NewVersions(config config.Versioning, meter metric.Meter) (*Versions, error) {
+	requests, err := meter.Int64Counter("http.server.api_version.requests",
+		metric.WithDescription("Requests by API version and route, deprecated aliases included"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api version counter: %w", err)
+	}
+
+	return &Versions{
+		requests: requests,
+
+		config: config,
+	}, nil
+}
+
+// SetConfig replaces the versioning config, applied to the following requests
+func (versions *Versions) SetConfig(config config.Versioning) {
+	versions.mu.Lock()
+	defer versions.mu.Unlock()
+
+	versions.config = config
+}
+
+// Handler creates a middleware for the route group of the given API version
+func (versions *Versions) Handler(version string) fiber.Handler {
+	return versions.handler(version, false)
+}
+
+// Alias creates a middleware for unversioned routes served as the given API
+// version. Responses carry the successor Link header, and the Deprecation and
+// Sunset headers once their dates are configured.
+func (versions *Versions) Alias(version string) fiber.Handler {
+	return versions.handler(version, true)
+}
+
+func (versions *Versions) handler(version string, deprecated bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals(versionLocal, version)
+
+		if deprecated {
+			versions.mu.RLock()
+			config := versions.config
+			versions.mu.RUnlock()
+
+			// Dates were checked when the config was loaded
+			if config.Deprecation != "" {
+				deprecation, _ := time.Parse(time.DateOnly, config.Deprecation)
+				c.Set(HeaderDeprecation, fmt.Sprintf("@%d", deprecation.Unix()))
+			}
+
+			if config.Sunset != "" {
+				sunset, _ := time.Parse(time.DateOnly, config.Sunset)
+				c.Set(HeaderSunset, sunset.Format(http.TimeFormat))
+			}
+
+			c.Append(fiber.HeaderLink, fmt.Sprintf(`</%s%s>; rel="successor-version"`, version, c.Path()))
+		}
+
+		span := trace.SpanFromContext(c.UserContext())
+		span.SetAttributes(
+			attribute.String("api.version", version),
+			attribute.Bool("api.deprecated", deprecated),
+		)
+
+		err := c.Next()
+
+		// The route is only known once the request was routed
+		versions.requests.Add(c.UserContext(), 1, metric.WithAttributes(
+			attribute.String("api.version", version),
+			attribute.Bool("api.deprecated", deprecated),
+			attribute.String("http.route", unversionedPath(routePath(c))),
+		))
+
+		return err
+	}
+}
+
+// APIVersion returns the API version the request was routed to
+func APIVersion(c *fiber.Ctx) string {
+	version, _ := c.Locals(versionLocal).(string)
+
+	return version
+}
+
+// unversionedPath drops the version prefix of a route path, "/v1/ping" is "/ping"
+func unversionedPath(path string) string {
+	if loc := versionPrefix.FindStringIndex(path); loc != nil {
+		return "/" + path[loc[1]:]
+	}
+
+	return path
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"service-a/util/config"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+func TestAliasHeaders(t *testing.T) {
+	tests := []struct {
+		name            string
+		config          config.Versioning
+		wantDeprecation string
+		wantSunset      string
+	}{
+		{name: "no dates"},
+		{
+			name:            "both dates",
+			config:          config.Versioning{Deprecation: "2026-10-19", Sunset: "2027-04-19"},
+			wantDeprecation: "@1792368000",
+			wantSunset:      "Mon, 19 Apr 2027 00:00:00 GMT",
+		},
+		{name: "sunset only", config: config.Versioning{Sunset: "2027-04-19"}, wantSunset: "Mon, 19 Apr 2027 00:00:00 GMT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			versions, err := NewVersions(tt.config, noop.NewMeterProvider().Meter("test"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			app := fiber.New()
+			app.Get("/ping", versions.Alias("v1"), func(c *fiber.Ctx) error {
+				return c.SendString("pong")
+			})
+
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/ping", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := resp.Header.Get(HeaderDeprecation); got != tt.wantDeprecation {
+				t.Errorf("Deprecation = %q, want %q", got, tt.wantDeprecation)
+			}
+
+			if got := resp.Header.Get(HeaderSunset); got != tt.wantSunset {
+				t.Errorf("Sunset = %q, want %q", got, tt.wantSunset)
+			}
+
+			if got := resp.Header.Get(fiber.HeaderLink); got != `</v1/ping>; rel="successor-version"` {
+				t.Errorf("Link = %q, want the v1 successor", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"service-a/adapter/service_b_adapter"
+	"service-a/util/logging"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type ChatParams struct {
+	Message  string `json:"message"`
+	Sequence int64  `json:"sequence"`
+}
+
+// Validate checks the params received from the client
+func (params *ChatParams) Validate() error {
+	if params.Message == "" {
+		return errors.New("message is required")
+	}
+
+	return nil
+}
+
+type ChatResult struct {
+	PongMessage string `json:"pong_message"`
+	Sequence    int64  `json:"sequence"`
+}
+
+// ChatSession relays the messages of a client session to a Chat call on
+// service-b. The call is opened on the first message and opened again after
+// it failed, so that a failed exchange does not end the session.
+type ChatSession struct {
+	service *Service
+
+	// ctx is the context of the session, the call lives as long as it
+	ctx    context.Context
+	stream *service_b_adapter.ChatStream
+}
+
+// OpenChat starts a chat session bound to ctx
+func (service *Service) OpenChat(ctx context.Context) *ChatSession {
+	return &ChatSession{
+		service: service,
+		ctx:     ctx,
+	}
+}
+
+// Exchange relays a single message and returns its pong
+func (session *ChatSession) Exchange(ctx context.Context, params *ChatParams) (*ChatResult, error) {
+	const op = "service.ChatSession.Exchange"
+
+	// Start span
+	ctx, span := session.service.tracer.Start(ctx, op)
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("service.operation", "chat"),
+		attribute.String("service.input.params", fmt.Sprintf("%+v", params)),
+	)
+
+	// Get logger with trace id
+	logger := logging.LogWithTrace(ctx, session.service.logger)
+
+	logger.WithFields(logrus.Fields{
+		"[op]":   op,
+		"params": params,
+	}).Info()
+
+	if session.stream == nil {
+		stream, err := session.service.serviceBAdapter.OpenChat(session.ctx)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+
+			return nil, err
+		}
+
+		session.stream = stream
+		span.AddEvent("chat_stream_opened")
+	}
+
+	data, err := session.stream.Exchange(ctx, params.Message, params.Sequence)
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"[op]":   op,
+			"params": params,
+			"error":  err,
+		}).Error()
+
+		// The call ended with the error, the next message opens a new one
+		session.Close()
+
+		// Record the error in the span
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, err
+	}
+
+	result := &ChatResult{
+		PongMessage: data.GetPongMessage(),
+		Sequence:    data.GetSequence(),
+	}
+
+	// Record success attributes
+	span.SetAttributes(
+		attribute.String("service.output.result", fmt.Sprintf("%+v", result)),
+	)
+	span.SetStatus(codes.Ok, "service operation completed successfully")
+
+	return result, nil
+}
+
+// Close ends the Chat call of the session, if any
+func (session *ChatSession) Close() {
+	if session.stream != nil {
+		session.stream.Close()
+		session.stream = nil
+	}
+}
@@ -2,10 +2,12 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"service-a/util/logging"
+	"service-a/util/wait"
 
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel/attribute"
@@ -16,6 +18,15 @@ type PingParams struct {
 	PingMessage string `json:"ping_message"`
 }
 
+// Validate checks the params received in a request body
+func (params *PingParams) Validate() error {
+	if params.PingMessage == "" {
+		return errors.New("ping_message is required")
+	}
+
+	return nil
+}
+
 type PingResult struct {
 	PongMessage string `json:"pong_message"`
 }
@@ -41,7 +52,20 @@ func (service *Service) Ping(ctx context.Context, params *PingParams) (*PingResu
 	}).Info()
 
 	// Simulate a service operation
-	time.Sleep(500 * time.Millisecond)
+	err := wait.Sleep(ctx, 500*time.Millisecond)
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"[op]":   op,
+			"params": params,
+			"error":  err,
+		}).Error()
+
+		// Record the error in the span
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, err
+	}
 
 	data, err := service.serviceBAdapter.Ping(ctx, params.PingMessage)
 	if err != nil {
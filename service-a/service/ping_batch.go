@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"service-a/util/logging"
+	"service-a/util/tracing"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type PingBatchParams struct {
+	Messages []string `json:"messages"`
+}
+
+// Validate checks the params received in a request body
+func (params *PingBatchParams) Validate(maxItems int) error {
+	if len(params.Messages) == 0 {
+		return errors.New("messages must hold at least one message")
+	}
+
+	if len(params.Messages) > maxItems {
+		return fmt.Errorf("messages must hold at most %d messages, got %d", maxItems, len(params.Messages))
+	}
+
+	for i, message := range params.Messages {
+		if message == "" {
+			return fmt.Errorf("messages[%d] must not be empty", i)
+		}
+	}
+
+	return nil
+}
+
+type PingBatchItem struct {
+	Index  int
+	Result *PingResult
+	Err    error
+}
+
+type PingBatchResult struct {
+	Items     []PingBatchItem
+	Succeeded int
+	Failed    int
+}
+
+// PingBatch pings service-b once per message, running at most
+// batch.concurrency calls at the same time. A failed item does not fail the
+// batch, its error is returned with the item.
+func (service *Service) PingBatch(ctx context.Context, params *PingBatchParams) (*PingBatchResult, error) {
+	const op = "service.Service.PingBatch"
+
+	// Start span
+	ctx, span := service.tracer.Start(ctx, op)
+	defer span.End()
+
+	// A reload applies to the following batches only
+	service.mu.RLock()
+	concurrency := service.batch.Concurrency
+	service.mu.RUnlock()
+
+	span.SetAttributes(
+		attribute.String("service.operation", "ping_batch"),
+		attribute.Int("service.batch.size", len(params.Messages)),
+		attribute.Int("service.batch.concurrency", concurrency),
+	)
+
+	// Get logger with trace id
+	logger := logging.LogWithTrace(ctx, service.logger)
+
+	logger.WithFields(logrus.Fields{
+		"[op]":        op,
+		"size":        len(params.Messages),
+		"concurrency": concurrency,
+	}).Info()
+
+	result := &PingBatchResult{
+		Items: make([]PingBatchItem, len(params.Messages)),
+	}
+
+	// Bound the number of calls in flight
+	slots := make(chan struct{}, max(concurrency, 1))
+
+	var wg sync.WaitGroup
+	for i, message := range params.Messages {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			result.Items[i] = service.pingBatchItem(ctx, i, message)
+		}()
+	}
+
+	wg.Wait()
+
+	for _, item := range result.Items {
+		if item.Err != nil {
+			result.Failed++
+		} else {
+			result.Succeeded++
+		}
+	}
+
+	// Record result attributes
+	span.SetAttributes(
+		attribute.Int("service.batch.succeeded", result.Succeeded),
+		attribute.Int("service.batch.failed", result.Failed),
+	)
+
+	if result.Failed > 0 {
+		logger.WithFields(logrus.Fields{
+			"[op]":      op,
+			"succeeded": result.Succeeded,
+			"failed":    result.Failed,
+		}).Warn()
+	}
+
+	span.SetStatus(codes.Ok, "service operation completed successfully")
+
+	return result, nil
+}
+
+// pingBatchItem pings a single message of a batch in its own child span
+func (service *Service) pingBatchItem(ctx context.Context, index int, message string) (item PingBatchItem) {
+	const op = "service.Service.PingBatch.item"
+
+	// Start span
+	ctx, span := service.tracer.Start(ctx, op)
+	defer span.End()
+
+	// Items run in their own goroutines, out of reach of the HTTP recovery; a
+	// panic only fails its item
+	defer func() {
+		if value := recover(); value != nil {
+			tracing.RecordPanic(ctx, service.logger, value)
+
+			item = PingBatchItem{Index: index, Err: fmt.Errorf("batch item panicked: %v", value)}
+		}
+	}()
+
+	span.SetAttributes(
+		attribute.Int("service.batch.index", index),
+		attribute.String("service.input.message", message),
+	)
+
+	result, err := service.Ping(ctx, &PingParams{
+		PingMessage: message,
+	})
+	if err != nil {
+		// Record the error in the span
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return PingBatchItem{Index: index, Err: err}
+	}
+
+	span.SetStatus(codes.Ok, "batch item completed successfully")
+
+	return PingBatchItem{Index: index, Result: result}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"service-a/adapter/service_b_adapter/pb"
+	"service-a/util/logging"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Limits of a ping stream request
+const (
+	MaxStreamCount    = 100
+	MaxStreamInterval = 10 * time.Second
+)
+
+type PingStreamParams struct {
+	PingMessage string        `json:"ping_message"`
+	Count       int           `json:"count"`
+	Interval    time.Duration `json:"interval"`
+}
+
+// Validate checks the params received in a request
+func (params *PingStreamParams) Validate() error {
+	if params.Count < 1 || params.Count > MaxStreamCount {
+		return fmt.Errorf("count must be between 1 and %d, got %d", MaxStreamCount, params.Count)
+	}
+
+	if params.Interval < 0 || params.Interval > MaxStreamInterval {
+		return fmt.Errorf("interval_ms must be between 0 and %d, got %d", MaxStreamInterval.Milliseconds(), params.Interval.Milliseconds())
+	}
+
+	return nil
+}
+
+type PingStreamResult struct {
+	PongMessage string `json:"pong_message"`
+	Sequence    int    `json:"sequence"`
+}
+
+// PingStream relays the pong messages streamed by service-b to emit
+func (service *Service) PingStream(ctx context.Context, params *PingStreamParams, emit func(*PingStreamResult) error) error {
+	const op = "service.Service.PingStream"
+
+	// Start span
+	ctx, span := service.tracer.Start(ctx, op)
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("service.operation", "ping_stream"),
+		attribute.String("service.input.params", fmt.Sprintf("%+v", params)),
+	)
+
+	// Get logger with trace id
+	logger := logging.LogWithTrace(ctx, service.logger)
+
+	logger.WithFields(logrus.Fields{
+		"[op]":   op,
+		"params": params,
+	}).Info()
+
+	err := service.serviceBAdapter.PingStream(ctx, params.PingMessage, params.Count, params.Interval, func(data *pb.PingStreamResponse) error {
+		return emit(&PingStreamResult{
+			PongMessage: data.GetPongMessage(),
+			Sequence:    int(data.GetSequence()),
+		})
+	})
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"[op]":   op,
+			"params": params,
+			"error":  err,
+		}).Error()
+
+		// Record the error in the span
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "service operation completed successfully")
+
+	return nil
+}
@@ -1,13 +1,19 @@
 package service
 
 import (
+	"sync"
+
 	"service-a/adapter/service_b_adapter"
+	"service-a/util/config"
 
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel/trace"
 )
 
 type Service struct {
+	mu    sync.RWMutex
+	batch config.Batch
+
 	logger *logrus.Logger
 	tracer trace.Tracer
 
@@ -15,14 +21,25 @@ type Service struct {
 }
 
 func NewService(
+	batch config.Batch,
 	logger *logrus.Logger,
 	tracer trace.Tracer,
 	serviceBAdapter *service_b_adapter.Adapter,
 ) *Service {
 	return &Service{
+		batch: batch,
+
 		logger: logger,
 		tracer: tracer,
 
 		serviceBAdapter: serviceBAdapter,
 	}
 }
+
+// SetConfig replaces the batch config, applied to the following batches
+func (service *Service) SetConfig(batch config.Batch) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	service.batch = batch
+}
@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+
+	"service-a/util/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrNoCredentials is returned when a request carries neither an API key nor
+// a bearer token
+var ErrNoCredentials = errors.New("missing credentials")
+
+// Authenticator checks the credentials of incoming requests
+type Authenticator struct {
+	// apiKeys maps every API key to its subject
+	apiKeys map[string]string
+
+	parser  *jwt.Parser
+	keyfunc jwt.Keyfunc
+}
+
+// NewAuthenticator loads the API keys and JWT verification keys of the auth config
+func NewAuthenticator(config config.Auth) (*Authenticator, error) {
+	apiKeys, err := parseAPIKeys(config.APIKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	authenticator := &Authenticator{
+		apiKeys: apiKeys,
+	}
+
+	keyfunc, err := loadKeyfunc(config.JWT)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyfunc != nil {
+		opts := []jwt.ParserOption{
+			jwt.WithValidMethods([]string{config.JWT.Algorithm}),
+			jwt.WithLeeway(config.JWT.Leeway),
+			jwt.WithExpirationRequired(),
+		}
+		if config.JWT.Issuer != "" {
+			opts = append(opts, jwt.WithIssuer(config.JWT.Issuer))
+		}
+		if config.JWT.Audience != "" {
+			opts = append(opts, jwt.WithAudience(config.JWT.Audience))
+		}
+
+		authenticator.parser = jwt.NewParser(opts...)
+		authenticator.keyfunc = keyfunc
+	}
+
+	return authenticator, nil
+}
+
+// Authenticate returns the principal identified by an API key or by the
+// value of an Authorization header holding a bearer token
+func (authenticator *Authenticator) Authenticate(apiKey, authorization string) (Principal, error) {
+	if apiKey != "" {
+		return authenticator.authenticateAPIKey(apiKey)
+	}
+
+	if authorization != "" {
+		token, ok := strings.CutPrefix(authorization, "Bearer ")
+		if !ok {
+			return Principal{}, errors.New("authorization header must hold a bearer token")
+		}
+
+		return authenticator.authenticateJWT(strings.TrimSpace(token))
+	}
+
+	return Principal{}, ErrNoCredentials
+}
+
+func (authenticator *Authenticator) authenticateAPIKey(apiKey string) (Principal, error) {
+	subject := ""
+
+	// Compare with every key in constant time, not to leak which one is close
+	for key, keySubject := range authenticator.apiKeys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) == 1 {
+			subject = keySubject
+		}
+	}
+
+	if subject == "" {
+		return Principal{}, errors.New("invalid API key")
+	}
+
+	return Principal{Subject: subject, Method: MethodAPIKey}, nil
+}
+
+func (authenticator *Authenticator) authenticateJWT(tokenString string) (Principal, error) {
+	if authenticator.parser == nil {
+		return Principal{}, errors.New("bearer tokens are not accepted")
+	}
+
+	claims := &jwt.RegisteredClaims{}
+
+	_, err := authenticator.parser.ParseWithClaims(tokenString, claims, authenticator.keyfunc)
+	if err != nil {
+		return Principal{}, fmt.Errorf("invalid bearer token: %w", err)
+	}
+
+	if claims.Subject == "" {
+		return Principal{}, errors.New("invalid bearer token: missing sub claim")
+	}
+
+	return Principal{Subject: claims.Subject, Method: MethodJWT}, nil
+}
+
+// parseAPIKeys parses "subject=key,subject2=key2"
+func parseAPIKeys(s string) (map[string]string, error) {
+	apiKeys := make(map[string]string)
+
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		subject, key, ok := strings.Cut(pair, "=")
+		subject, key = strings.TrimSpace(subject), strings.TrimSpace(key)
+		if !ok || subject == "" || key == "" {
+			return nil, errors.New("auth.api_keys must be in subject=key,subject2=key2 form")
+		}
+
+		apiKeys[key] = subject
+	}
+
+	return apiKeys, nil
+}
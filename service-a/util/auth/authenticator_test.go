@@ -0,0 +1,166 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"service-a/util/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "jwt-secret"
+
+var jwtConfig = config.AuthJWT{
+	Algorithm: "HS256",
+	Secret:    testSecret,
+	Issuer:    "issuer",
+	Audience:  "service-a",
+	Leeway:    time.Second,
+}
+
+func sign(t *testing.T, claims jwt.RegisteredClaims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+func TestAuthenticate(t *testing.T) {
+	authenticator, err := NewAuthenticator(config.Auth{APIKeys: "alice=k1, bob=k2", JWT: jwtConfig})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	claims := func(mutate func(*jwt.RegisteredClaims)) jwt.RegisteredClaims {
+		c := jwt.RegisteredClaims{
+			Subject:   "carol",
+			Issuer:    "issuer",
+			Audience:  jwt.ClaimStrings{"service-a"},
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		}
+		if mutate != nil {
+			mutate(&c)
+		}
+
+		return c
+	}
+
+	tests := []struct {
+		name          string
+		apiKey        string
+		authorization string
+		want          Principal
+		wantErr       bool
+	}{
+		{name: "valid key", apiKey: "k2", want: Principal{Subject: "bob", Method: MethodAPIKey}},
+		{name: "wrong key", apiKey: "k3", wantErr: true},
+		{name: "key takes precedence over token", apiKey: "k1", authorization: "Bearer junk", want: Principal{Subject: "alice", Method: MethodAPIKey}},
+		{name: "valid JWT", authorization: "Bearer " + sign(t, claims(nil)), want: Principal{Subject: "carol", Method: MethodJWT}},
+		{
+			name: "expired JWT",
+			authorization: "Bearer " + sign(t, claims(func(c *jwt.RegisteredClaims) {
+				c.ExpiresAt = jwt.NewNumericDate(now.Add(-time.Minute))
+			})),
+			wantErr: true,
+		},
+		{
+			name: "JWT without expiry",
+			authorization: "Bearer " + sign(t, claims(func(c *jwt.RegisteredClaims) {
+				c.ExpiresAt = nil
+			})),
+			wantErr: true,
+		},
+		{
+			name: "wrong-audience JWT",
+			authorization: "Bearer " + sign(t, claims(func(c *jwt.RegisteredClaims) {
+				c.Audience = jwt.ClaimStrings{"service-b"}
+			})),
+			wantErr: true,
+		},
+		{
+			name: "wrong-issuer JWT",
+			authorization: "Bearer " + sign(t, claims(func(c *jwt.RegisteredClaims) {
+				c.Issuer = "someone"
+			})),
+			wantErr: true,
+		},
+		{
+			name: "JWT without subject",
+			authorization: "Bearer " + sign(t, claims(func(c *jwt.RegisteredClaims) {
+				c.Subject = ""
+			})),
+			wantErr: true,
+		},
+		{name: "not a bearer token", authorization: "Basic YWxpY2U6azE=", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := authenticator.Authenticate(tt.apiKey, tt.authorization)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Fatalf("Authenticate() = %+v, %v, want %+v, error=%v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuthenticateWithoutCredentials(t *testing.T) {
+	authenticator, err := NewAuthenticator(config.Auth{APIKeys: "alice=k1", JWT: config.AuthJWT{Algorithm: "HS256"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := authenticator.Authenticate("", ""); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("Authenticate() = %v, want ErrNoCredentials", err)
+	}
+
+	// Without JWT keys, bearer tokens are refused rather than trusted
+	if _, err := authenticator.Authenticate("", "Bearer "+sign(t, jwt.RegisteredClaims{Subject: "carol"})); err == nil {
+		t.Fatal("bearer token accepted without JWT keys")
+	}
+}
+
+func TestForwardedTokenRoundTrip(t *testing.T) {
+	forwarder := NewForwarder(config.AuthForward{Secret: testSecret, Issuer: "service-a", Audience: "service-b", TTL: time.Minute})
+
+	token, err := forwarder.Token(Principal{Subject: "alice", Method: MethodAPIKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Verified with the secret, issuer and audience service-b expects
+	authenticator, err := NewAuthenticator(config.Auth{JWT: config.AuthJWT{
+		Algorithm: "HS256",
+		Secret:    testSecret,
+		Issuer:    "service-a",
+		Audience:  "service-b",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	principal, err := authenticator.Authenticate("", "Bearer "+token)
+	if err != nil || principal.Subject != "alice" {
+		t.Fatalf("Authenticate() = %+v, %v, want alice", principal, err)
+	}
+
+	claims := &forwardedClaims{}
+	if _, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) { return []byte(testSecret), nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(claims.AMR) != 1 || claims.AMR[0] != MethodAPIKey {
+		t.Fatalf("amr = %v, want [%s]", claims.AMR, MethodAPIKey)
+	}
+
+	if ttl := claims.ExpiresAt.Sub(claims.IssuedAt.Time); ttl != time.Minute {
+		t.Fatalf("token lives %s, want 1m", ttl)
+	}
+}
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"service-a/util/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// loadKeyfunc returns the function giving the key that verifies a token, or
+// nil when no JWT key is configured
+func loadKeyfunc(config config.AuthJWT) (jwt.Keyfunc, error) {
+	switch config.Algorithm {
+	case "HS256":
+		if config.Secret == "" {
+			return nil, nil
+		}
+
+		secret := []byte(config.Secret)
+
+		return func(*jwt.Token) (any, error) { return secret, nil }, nil
+
+	case "RS256":
+		if config.PublicKeyFile != "" {
+			pem, err := os.ReadFile(config.PublicKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read auth.jwt.public_key_file: %w", err)
+			}
+
+			key, err := jwt.ParseRSAPublicKeyFromPEM(pem)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse auth.jwt.public_key_file: %w", err)
+			}
+
+			return func(*jwt.Token) (any, error) { return key, nil }, nil
+		}
+
+		if config.JWKSFile != "" {
+			keys, err := readJWKS(config.JWKSFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load auth.jwt.jwks_file: %w", err)
+			}
+
+			return func(token *jwt.Token) (any, error) {
+				kid, _ := token.Header["kid"].(string)
+
+				key, ok := keys[kid]
+				if !ok && kid == "" && len(keys) == 1 {
+					for _, only := range keys {
+						key, ok = only, true
+					}
+				}
+				if !ok {
+					return nil, fmt.Errorf("unknown key id %q", kid)
+				}
+
+				return key, nil
+			}, nil
+		}
+
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("unsupported auth.jwt.algorithm %q", config.Algorithm)
+}
+
+// jwk is the subset of a JSON Web Key needed for RSA signature keys
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// readJWKS reads the RSA signature keys of a JWKS document, by key id
+func readJWKS(path string) (map[string]*rsa.PublicKey, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+
+	err = json.Unmarshal(content, &set)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, key := range set.Keys {
+		if key.Kty != "RSA" || (key.Use != "" && key.Use != "sig") {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(key.N)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: invalid modulus: %w", key.Kid, err)
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(key.E)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: invalid exponent: %w", key.Kid, err)
+		}
+
+		keys[key.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("no RSA signature key found")
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,32 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Entry is a cached response
+type Entry struct {
+	Status      int
+	ContentType string
+	Body        []byte
+	ETag        string
+
+	Stored  time.Time
+	Expires time.Time
+}
+
+// Size is the number of bytes the entry accounts for in a bounded store
+func (entry *Entry) Size() int {
+	return len(entry.Body) + len(entry.ContentType) + len(entry.ETag)
+}
+
+// Store keeps the cached responses. Implementations must be safe for
+// concurrent use and must not return expired entries.
+type Store interface {
+	// Get returns the entry of key, nil when absent or expired at now
+	Get(ctx context.Context, key string, now time.Time) (*Entry, error)
+
+	// Set stores entry under key, replacing any previous one
+	Set(ctx context.Context, key string, entry Entry) error
+}
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+type item struct {
+	key   string
+	entry Entry
+}
+
+// LRU keeps the cached responses in process memory, bounded by a number of
+// entries and a number of bytes. The least recently used entries are evicted
+// first.
+type LRU struct {
+	maxEntries int
+	maxBytes   int
+
+	mu    sync.Mutex
+	size  int
+	order *list.List
+	items map[string]*list.Element
+}
+
+// NewLRU creates an empty LRU holding at most maxEntries entries and
+// maxBytes bytes
+func NewLRU(maxEntries, maxBytes int) *LRU {
+	return &LRU{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+func (lru *LRU) Get(_ context.Context, key string, now time.Time) (*Entry, error) {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	element, ok := lru.items[key]
+	if !ok {
+		return nil, nil
+	}
+
+	it := element.Value.(*item)
+	if !now.Before(it.entry.Expires) {
+		lru.remove(element)
+
+		return nil, nil
+	}
+
+	lru.order.MoveToFront(element)
+	entry := it.entry
+
+	return &entry, nil
+}
+
+func (lru *LRU) Set(_ context.Context, key string, entry Entry) error {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	if element, ok := lru.items[key]; ok {
+		lru.remove(element)
+	}
+
+	// An entry larger than the whole cache is not kept
+	if entry.Size() > lru.maxBytes {
+		return nil
+	}
+
+	lru.items[key] = lru.order.PushFront(&item{key: key, entry: entry})
+	lru.size += entry.Size()
+
+	for lru.order.Len() > lru.maxEntries || lru.size > lru.maxBytes {
+		lru.remove(lru.order.Back())
+	}
+
+	return nil
+}
+
+func (lru *LRU) remove(element *list.Element) {
+	it := element.Value.(*item)
+
+	lru.order.Remove(element)
+	delete(lru.items, it.key)
+	lru.size -= it.entry.Size()
+}
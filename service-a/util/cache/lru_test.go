@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+var ctx = context.Background()
+
+func entry(body string, expires time.Time) Entry {
+	return Entry{Status: 200, Body: []byte(body), Expires: expires}
+}
+
+// checkSize verifies the byte accounting against the entries actually held
+func checkSize(t *testing.T, lru *LRU) {
+	t.Helper()
+
+	size := 0
+	for element := lru.order.Front(); element != nil; element = element.Next() {
+		size += element.Value.(*item).entry.Size()
+	}
+
+	if lru.size != size {
+		t.Fatalf("size = %d, entries hold %d bytes", lru.size, size)
+	}
+
+	if len(lru.items) != lru.order.Len() {
+		t.Fatalf("%d items indexed, %d in order", len(lru.items), lru.order.Len())
+	}
+}
+
+func TestLRU(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Minute)
+
+	type op struct {
+		set  string // key to set, or
+		get  string // key to get
+		body string
+		want bool // whether get finds the key
+	}
+
+	tests := []struct {
+		name       string
+		maxEntries int
+		maxBytes   int
+		ops        []op
+		size       int
+	}{
+		{
+			name:       "least recently used entry is evicted above max entries",
+			maxEntries: 2,
+			maxBytes:   100,
+			ops: []op{
+				{set: "a", body: "1"},
+				{set: "b", body: "2"},
+				{get: "a", want: true},
+				{set: "c", body: "3"},
+				{get: "b", want: false},
+				{get: "a", want: true},
+				{get: "c", want: true},
+			},
+			size: 2,
+		},
+		{
+			name:       "entries are evicted until the bytes fit",
+			maxEntries: 10,
+			maxBytes:   6,
+			ops: []op{
+				{set: "a", body: "aaa"},
+				{set: "b", body: "bbb"},
+				{set: "c", body: "cccc"},
+				{get: "a", want: false},
+				{get: "b", want: false},
+				{get: "c", want: true},
+			},
+			size: 4,
+		},
+		{
+			name:       "replacing an entry accounts for the new size only",
+			maxEntries: 10,
+			maxBytes:   100,
+			ops: []op{
+				{set: "a", body: "short"},
+				{set: "a", body: "a longer body"},
+				{set: "a", body: "mid"},
+				{get: "a", want: true},
+			},
+			size: 3,
+		},
+		{
+			name:       "an entry larger than the cache is dropped with the one it replaces",
+			maxEntries: 10,
+			maxBytes:   4,
+			ops: []op{
+				{set: "a", body: "aa"},
+				{set: "b", body: "bb"},
+				{set: "a", body: "too large"},
+				{get: "a", want: false},
+				{get: "b", want: true},
+			},
+			size: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lru := NewLRU(tt.maxEntries, tt.maxBytes)
+
+			for i, op := range tt.ops {
+				if op.set != "" {
+					if err := lru.Set(ctx, op.set, entry(op.body, later)); err != nil {
+						t.Fatalf("op %d: %v", i, err)
+					}
+				} else {
+					got, _ := lru.Get(ctx, op.get, now)
+					if (got != nil) != op.want {
+						t.Fatalf("op %d: Get(%q) = %v, want found=%v", i, op.get, got, op.want)
+					}
+				}
+
+				checkSize(t, lru)
+			}
+
+			if lru.size != tt.size {
+				t.Fatalf("size = %d, want %d", lru.size, tt.size)
+			}
+		})
+	}
+}
+
+func TestLRUExpiredEntryIsRemoved(t *testing.T) {
+	lru := NewLRU(10, 100)
+	now := time.Now()
+
+	lru.Set(ctx, "a", entry("pong", now.Add(time.Second)))
+
+	if got, _ := lru.Get(ctx, "a", now.Add(time.Second)); got != nil {
+		t.Fatalf("Get() = %+v, want nil once expired", got)
+	}
+
+	if lru.size != 0 || lru.order.Len() != 0 {
+		t.Fatalf("size = %d, %d in order, want the expired entry removed", lru.size, lru.order.Len())
+	}
+}
+
+func TestLRUConcurrent(t *testing.T) {
+	lru := NewLRU(8, 64)
+	now := time.Now()
+	later := now.Add(time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 200; j++ {
+				key := string(rune('a' + (i+j)%12))
+
+				lru.Set(ctx, key, entry(key+"-body", later))
+				lru.Get(ctx, key, now)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	checkSize(t, lru)
+
+	if lru.order.Len() > 8 || lru.size > 64 {
+		t.Fatalf("%d entries, %d bytes, over the bounds", lru.order.Len(), lru.size)
+	}
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadConfigReadsMapsFromEnv(t *testing.T) {
+	t.Setenv("SERVICE_A_TIMEOUTS_ROUTES", `{"POST /ping/batch": "30s"}`)
+	t.Setenv("SERVICE_A_RATE_LIMIT_ROUTES", `{"POST /ping/batch": {"rate": 1, "burst": 2}}`)
+	t.Setenv("SERVICE_A_CACHE_ROUTES", `{"GET /ping": "1m"}`)
+
+	file := writeConfig(t, minimal)
+
+	config, err := LoadConfig(file, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Keys are lowercased, as they are when read from a file
+	if got := config.Timeouts.Routes["post /ping/batch"]; got != 30*time.Second {
+		t.Errorf("timeouts.routes = %v, want 30s for post /ping/batch", config.Timeouts.Routes)
+	}
+
+	if got := config.RateLimit.Routes["post /ping/batch"]; got != (RateLimitRouteRule{Rate: 1, Burst: 2}) {
+		t.Errorf("rate_limit.routes = %v, want rate 1 burst 2 for post /ping/batch", config.RateLimit.Routes)
+	}
+
+	if got := config.Cache.Routes["get /ping"]; got != time.Minute {
+		t.Errorf("cache.routes = %v, want 1m for get /ping", config.Cache.Routes)
+	}
+
+	settings, err := Explain(file, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, setting := range settings {
+		if setting.Key == "timeouts.routes" && setting.Source != "env SERVICE_A_TIMEOUTS_ROUTES" {
+			t.Errorf("timeouts.routes source = %q", setting.Source)
+		}
+	}
+}
+
+func TestLoadConfigRejectsMapEnvThatIsNotJSON(t *testing.T) {
+	t.Setenv("SERVICE_A_TIMEOUTS_ROUTES", "POST /ping/batch=30s")
+
+	if _, err := LoadConfig(writeConfig(t, minimal), ""); err == nil || !strings.Contains(err.Error(), "SERVICE_A_TIMEOUTS_ROUTES") {
+		t.Fatalf("LoadConfig() = %v, want the malformed variable reported", err)
+	}
+}
@@ -1,26 +1,173 @@
 package config
 
+import "time"
+
 // App config
 
 type App struct {
-	Name               string `mapstructure:"name"`
-	Host               string `mapstructure:"host"` // Bind address (0.0.0.0 for listening)
-	Port               int    `mapstructure:"port"`
-	RegisterAddress    string `mapstructure:"register_address"`     // Address for service registration
-	HealthCheckAddress string `mapstructure:"health_check_address"` // Address for Consul health checks
+	Name               string        `mapstructure:"name" validate:"required"`
+	Host               string        `mapstructure:"host" default:"0.0.0.0"` // Bind address (0.0.0.0 for listening)
+	Port               int           `mapstructure:"port" default:"4000" validate:"port"`
+	RegisterAddress    string        `mapstructure:"register_address"`                                 // Address for service registration
+	HealthCheckAddress string        `mapstructure:"health_check_address"`                             // Address for Consul health checks
+	ShutdownTimeout    time.Duration `mapstructure:"shutdown_timeout" default:"15s" validate:"min=0s"` // Time allowed to drain requests and flush telemetry
 }
 
 // Service B config
 
 type ServiceB struct {
-	Name string `mapstructure:"name"`
-	Host string `mapstructure:"host"`
-	Port int    `mapstructure:"port"`
+	Name    string        `mapstructure:"name" default:"service-b" validate:"required"`
+	Host    string        `mapstructure:"host" validate:"required"`
+	Port    int           `mapstructure:"port" default:"50051" validate:"port"`
+	Timeout time.Duration `mapstructure:"timeout" default:"0s" validate:"min=0s" reload:"live"` // Deadline of a unary call, 0 leaves it to the request deadline
 }
 
 // Otel tracer config
 
 type OtelTracer struct {
-	Name     string `mapstructure:"name"`
-	Endpoint string `mapstructure:"endpoint"`
+	Name           string        `mapstructure:"name" validate:"required"`
+	Endpoint       string        `mapstructure:"endpoint" validate:"required,hostport"`
+	SampleRatio    float64       `mapstructure:"sample_ratio" default:"1.0" validate:"min=0,max=1" reload:"live"` // Fraction of new traces to sample (0..1)
+	Headers        string        `mapstructure:"headers" secret:"true"`                                           // Exporter headers as "key=value,key2=value2"
+	MetricInterval time.Duration `mapstructure:"metric_interval" default:"15s" validate:"min=1s"`                 // Time between two metric exports
+}
+
+// Logging config
+
+type Logging struct {
+	Level    string          `mapstructure:"level" default:"debug" validate:"oneof=trace|debug|info|warn|error" reload:"live"`
+	Sampling LoggingSampling `mapstructure:"sampling"`
+	Redact   []string        `mapstructure:"redact" default:"authorization,password,secret,token" reload:"live"` // Fields whose value is never logged
+}
+
+type LoggingSampling struct {
+	Interval    time.Duration `mapstructure:"interval" default:"1s" validate:"min=0s" reload:"live"` // Sampling window, e.g. "1s"
+	Limit       int           `mapstructure:"limit" default:"10" validate:"min=0" reload:"live"`     // Entries kept per [op]/message per window (0 disables sampling)
+	KeepSampled bool          `mapstructure:"keep_sampled" default:"false" reload:"live"`            // Never sample the lines of sampled traces, all of them at a sample ratio of 1
+}
+
+// Trace response config
+
+type TraceResponse struct {
+	Traceresponse bool   `mapstructure:"traceresponse" default:"true"`         // Send the W3C traceresponse header
+	TraceIDHeader string `mapstructure:"trace_id_header" default:"X-Trace-Id"` // Header carrying the trace id, empty to disable
+	URLTemplate   string `mapstructure:"url_template"`                         // Trace link, e.g. "http://localhost:16686/trace/{trace_id}"
+}
+
+// Health config
+
+type Health struct {
+	Timeout time.Duration `mapstructure:"timeout" default:"2s" validate:"min=0s"` // Time allowed to each readiness check
+	Traced  bool          `mapstructure:"traced" default:"false"`                 // Trace and access log the probe endpoints
+}
+
+// Batch ping config
+
+type Batch struct {
+	MaxItems    int `mapstructure:"max_items" default:"100" validate:"min=1"`               // Messages accepted by a single batch request
+	Concurrency int `mapstructure:"concurrency" default:"4" validate:"min=1" reload:"live"` // Calls to service-b running in parallel per batch
+}
+
+// Request timeouts config
+
+type Timeouts struct {
+	Default time.Duration            `mapstructure:"default" default:"10s" validate:"min=0s" reload:"live"` // Deadline of a request, 0 disables it
+	Max     time.Duration            `mapstructure:"max" default:"60s" validate:"min=0s" reload:"live"`     // Upper bound of the X-Request-Timeout header, 0 ignores the header
+	Routes  map[string]time.Duration `mapstructure:"routes" reload:"live"`                                  // Per route deadlines keyed "METHOD /path", e.g. "POST /ping/batch"
+}
+
+// Rate limit config
+
+type RateLimit struct {
+	Enabled    bool                          `mapstructure:"enabled" default:"false" reload:"live"`
+	KeyBy      string                        `mapstructure:"key_by" default:"ip" validate:"oneof=ip|api_key|header" reload:"live"` // What identifies a client, api_key being the authenticated principal
+	Header     string                        `mapstructure:"header" reload:"live"`                                                 // Header identifying the client when key_by is "header"
+	Rate       float64                       `mapstructure:"rate" default:"10" validate:"min=0" reload:"live"`                     // Requests per second a client earns
+	Burst      int                           `mapstructure:"burst" default:"20" validate:"min=0" reload:"live"`                    // Requests a client can make at once, 0 disables the limit
+	Routes     map[string]RateLimitRouteRule `mapstructure:"routes" reload:"live"`                                                 // Per route limits keyed "METHOD /path", e.g. "POST /ping/batch"
+	MaxBuckets int                           `mapstructure:"max_buckets" default:"10000" validate:"min=1"`                         // Client buckets kept at most, least recently used first out
+}
+
+type RateLimitRouteRule struct {
+	Rate  float64 `mapstructure:"rate"`
+	Burst int     `mapstructure:"burst"`
+}
+
+// Auth config
+
+type Auth struct {
+	Enabled bool        `mapstructure:"enabled" default:"false"` // Reject requests without valid credentials
+	APIKeys string      `mapstructure:"api_keys" secret:"true"`  // Static API keys as "subject=key,subject2=key2", sent in X-Api-Key
+	JWT     AuthJWT     `mapstructure:"jwt"`
+	Forward AuthForward `mapstructure:"forward"`
+}
+
+type AuthJWT struct {
+	Algorithm     string        `mapstructure:"algorithm" default:"HS256" validate:"oneof=HS256|RS256"`
+	Secret        string        `mapstructure:"secret" secret:"true"`                   // HS256 shared secret
+	PublicKeyFile string        `mapstructure:"public_key_file"`                        // RS256 PEM public key
+	JWKSFile      string        `mapstructure:"jwks_file"`                              // RS256 keys as a JWKS document, picked by "kid"
+	Issuer        string        `mapstructure:"issuer"`                                 // Required "iss" claim, if set
+	Audience      string        `mapstructure:"audience"`                               // Required "aud" claim, if set
+	Leeway        time.Duration `mapstructure:"leeway" default:"30s" validate:"min=0s"` // Clock skew tolerated on "exp" and "nbf"
+}
+
+type AuthForward struct {
+	Secret   string        `mapstructure:"secret" secret:"true"` // HS256 secret shared with service-b, empty disables forwarding
+	Issuer   string        `mapstructure:"issuer" default:"service-a"`
+	Audience string        `mapstructure:"audience" default:"service-b"`
+	TTL      time.Duration `mapstructure:"ttl" default:"1m" validate:"min=1s"` // Lifetime of a forwarded token
+}
+
+// OpenAPI config
+
+type OpenAPI struct {
+	Enabled  bool `mapstructure:"enabled" default:"true"`                 // Serve the document at /openapi.json and its UI at /docs
+	Validate bool `mapstructure:"validate" default:"false" reload:"live"` // Reject requests not matching the document with a 400
+}
+
+// CORS config
+
+type CORS struct {
+	// "https://app.example.com", "https://*.example.com" for its subdomains,
+	// or "*"; empty denies every cross-origin request
+	AllowOrigins []string `mapstructure:"allow_origins" validate:"origins" reload:"live"`
+	// Methods and request headers allowed by preflight requests
+	AllowMethods []string `mapstructure:"allow_methods" default:"GET,POST,HEAD" reload:"live"`
+	AllowHeaders []string `mapstructure:"allow_headers" default:"Origin,Content-Type,Accept,Authorization,X-Api-Key,X-Request-Timeout,Idempotency-Key,If-None-Match,Cache-Control,traceparent,tracestate,baggage" reload:"live"`
+	// Response headers readable by the browser
+	ExposeHeaders []string `mapstructure:"expose_headers" default:"X-Trace-Id,X-Trace-Url,traceresponse,RateLimit-Limit,RateLimit-Remaining,RateLimit-Reset,RateLimit-Policy,Retry-After,Idempotent-Replayed,Deprecation,Sunset,Link,ETag,Age" reload:"live"`
+	// Allow cookies and Authorization, never with the "*" origin
+	AllowCredentials bool `mapstructure:"allow_credentials" default:"false" reload:"live"`
+	// How long browsers cache a preflight response
+	MaxAge time.Duration `mapstructure:"max_age" default:"10m" validate:"min=0s" reload:"live"`
+}
+
+// API versioning config
+
+type Versioning struct {
+	Deprecation string `mapstructure:"deprecation" validate:"date" reload:"live"` // Date since which the unversioned routes are deprecated, no Deprecation header when empty
+	Sunset      string `mapstructure:"sunset" validate:"date" reload:"live"`      // Date after which the unversioned routes may be removed, no Sunset header when empty
+}
+
+// Idempotency config
+
+type Idempotency struct {
+	Enabled       bool          `mapstructure:"enabled" default:"true" reload:"live"`
+	TTL           time.Duration `mapstructure:"ttl" default:"24h" validate:"min=1s" reload:"live"`                  // How long a response is replayed for its key
+	InProgressTTL time.Duration `mapstructure:"in_progress_ttl" default:"2m" validate:"min=1s" reload:"live"`       // How long a key stays claimed by a request that never completes
+	Conflict      string        `mapstructure:"conflict" default:"wait" validate:"oneof=wait|reject" reload:"live"` // What a duplicate of a request in progress does: wait for its response or get a 409
+	WaitTimeout   time.Duration `mapstructure:"wait_timeout" default:"10s" validate:"min=0s" reload:"live"`         // Longest wait before a duplicate gets a 409
+	MaxEntries    int           `mapstructure:"max_entries" default:"10000" validate:"min=1"`                       // Keys kept at most, oldest first out
+	MaxBytes      int           `mapstructure:"max_bytes" default:"16777216" validate:"min=1"`                      // Bytes of stored response bodies kept at most
+}
+
+// Response cache config
+
+type Cache struct {
+	Enabled    bool                     `mapstructure:"enabled" default:"false" reload:"live"`
+	TTL        time.Duration            `mapstructure:"ttl" default:"5s" validate:"min=0s" reload:"live"` // How long a GET response is served from the cache, 0 disables it
+	Routes     map[string]time.Duration `mapstructure:"routes" reload:"live"`                             // Per route TTLs keyed "METHOD /path", e.g. "GET /ping"
+	MaxEntries int                      `mapstructure:"max_entries" default:"1000" validate:"min=1"`      // Responses kept at most, least recently used first out
+	MaxBytes   int                      `mapstructure:"max_bytes" default:"8388608" validate:"min=1"`     // Bytes of response bodies kept at most
 }
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSecretsAreNeverPrinted(t *testing.T) {
+	dir := t.TempDir()
+
+	jwtFile := filepath.Join(dir, "jwt-secret")
+	if err := os.WriteFile(jwtFile, []byte("jwt-from-env-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	forwardFile := filepath.Join(dir, "forward-secret")
+	if err := os.WriteFile(forwardFile, []byte("forward-from-ref"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// One secret per source: the file, a *_FILE variable and a file:// reference
+	file := writeConfig(t, `{
+  "app": {"name": "service-a"},
+  "service_b": {"host": "localhost"},
+  "otel_tracer": {"name": "tracer", "endpoint": "localhost:4317", "headers": "api-key=headers-from-file"},
+  "auth": {"forward": {"secret": "file://`+forwardFile+`"}}
+}`)
+	t.Setenv("SERVICE_A_AUTH_JWT_SECRET_FILE", jwtFile)
+
+	secrets := []string{"headers-from-file", "jwt-from-env-file", "forward-from-ref"}
+
+	config, err := LoadConfig(file, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The secrets themselves are loaded...
+	if config.OtelTracer.Headers != "api-key=headers-from-file" || config.Auth.JWT.Secret != "jwt-from-env-file" || config.Auth.Forward.Secret != "forward-from-ref" {
+		t.Fatalf("secrets not loaded: %q %q %q", config.OtelTracer.Headers, config.Auth.JWT.Secret, config.Auth.Forward.Secret)
+	}
+
+	// ...but never printed
+	masked := config.Masked()
+	if masked.OtelTracer.Headers != secretMask || masked.Auth.JWT.Secret != secretMask || masked.Auth.Forward.Secret != secretMask {
+		t.Fatalf("secrets not masked: %+v", masked.Auth)
+	}
+
+	if masked.Auth.APIKeys != "" {
+		t.Fatalf("unset secret masked as %q, want it left empty", masked.Auth.APIKeys)
+	}
+
+	if config.Auth.JWT.Secret != "jwt-from-env-file" {
+		t.Fatal("Masked changed the config it was called on")
+	}
+
+	settings, err := Explain(file, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var printed strings.Builder
+	sources := make(map[string]string)
+	for _, setting := range settings {
+		fmt.Fprintf(&printed, "%s %s %s\n", setting.Key, setting.Value, setting.Source)
+		sources[setting.Key] = setting.Source
+	}
+
+	for name, output := range map[string]string{
+		"String()":     config.String(),
+		"%v":           fmt.Sprintf("%v", config),
+		"%+v":          fmt.Sprintf("%+v", config),
+		"config print": printed.String(),
+	} {
+		for _, secret := range secrets {
+			if strings.Contains(output, secret) {
+				t.Errorf("%s prints %q:\n%s", name, secret, output)
+			}
+		}
+	}
+
+	if sources["auth.jwt.secret"] != "env SERVICE_A_AUTH_JWT_SECRET_FILE" || !strings.HasSuffix(sources["auth.forward.secret"], "file://"+forwardFile) {
+		t.Fatalf("secret sources = %q, %q", sources["auth.jwt.secret"], sources["auth.forward.secret"])
+	}
+}
+
+func TestMissingSecretFile(t *testing.T) {
+	file := writeConfig(t, minimal)
+	t.Setenv("SERVICE_A_AUTH_JWT_SECRET_FILE", filepath.Join(t.TempDir(), "missing"))
+
+	if _, err := LoadConfig(file, ""); err == nil || !strings.Contains(err.Error(), "SERVICE_A_AUTH_JWT_SECRET_FILE") {
+		t.Fatalf("LoadConfig() = %v, want the missing secret file reported", err)
+	}
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// minimal holds the keys without defaults that a valid config needs
+const minimal = `{
+  "app": {"name": "service-a"},
+  "service_b": {"host": "localhost"},
+  "otel_tracer": {"name": "tracer", "endpoint": "localhost:4317"}
+}`
+
+// writeConfig writes content to a config.json of its own and returns its path
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return file
+}
+
+func TestCheckRule(t *testing.T) {
+	tests := []struct {
+		rule    string
+		value   any
+		wantErr bool
+	}{
+		{rule: "required", value: "service-a"},
+		{rule: "required", value: "", wantErr: true},
+		{rule: "port", value: 4000},
+		{rule: "port", value: 0, wantErr: true},
+		{rule: "port", value: 65536, wantErr: true},
+		{rule: "hostport", value: "localhost:4317"},
+		{rule: "hostport", value: ""},
+		{rule: "hostport", value: "localhost", wantErr: true},
+		{rule: "hostport", value: ":4317", wantErr: true},
+		{rule: "hostport", value: "localhost:http", wantErr: true},
+		{rule: "oneof=ip|header", value: "header"},
+		{rule: "oneof=ip|header", value: "cookie", wantErr: true},
+		{rule: "min=1", value: 1},
+		{rule: "min=1", value: 0, wantErr: true},
+		{rule: "max=1", value: 0.5},
+		{rule: "max=1", value: 1.5, wantErr: true},
+		{rule: "min=1s", value: time.Second},
+		{rule: "min=1s", value: time.Millisecond, wantErr: true},
+		{rule: "date", value: "2027-04-19"},
+		{rule: "date", value: ""},
+		{rule: "date", value: "19/04/2027", wantErr: true},
+		{rule: "origins", value: []string{"*", "https://app.example.com", "https://*.example.com", "http://localhost:3000"}},
+		{rule: "origins", value: []string{"https://app.example.com/path"}, wantErr: true},
+		{rule: "origins", value: []string{"app.example.com"}, wantErr: true},
+		{rule: "unknown", value: "x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %v", tt.rule, tt.value), func(t *testing.T) {
+			err := checkRule(reflect.ValueOf(tt.value), tt.rule)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkRule(%v, %q) = %v, want error=%v", tt.value, tt.rule, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadConfigAppliesDefaults(t *testing.T) {
+	config, err := LoadConfig(writeConfig(t, minimal), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.App.Port != 4000 || config.ServiceB.Port != 50051 || config.Logging.Level != "debug" || config.Timeouts.Default != 10*time.Second {
+		t.Fatalf("defaults not applied: %+v", config)
+	}
+}
+
+func TestLoadConfigReportsEveryError(t *testing.T) {
+	_, err := LoadConfig(writeConfig(t, `{
+  "app": {"port": 70000},
+  "service_b": {"host": "localhost"},
+  "otel_tracer": {"name": "tracer", "endpoint": "localhost", "sample_ratio": 2},
+  "logging": {"level": "loud"},
+  "timeouts": {"default": "-1s"},
+  "cors": {"allow_origins": ["*"], "allow_credentials": true}
+}`), "")
+	if err == nil {
+		t.Fatal("invalid config accepted")
+	}
+
+	for _, want := range []string{
+		"app.name: is required",
+		"app.port: must be a port",
+		"otel_tracer.endpoint: must be in host:port form",
+		"otel_tracer.sample_ratio: must be at most 1",
+		"logging.level: must be one of",
+		"timeouts.default: must be at least 0s",
+		"cors.allow_origins: must not hold \"*\"",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not report %q:\n%v", want, err)
+		}
+	}
+}
+
+func TestValidateVersioningDates(t *testing.T) {
+	tests := []struct {
+		deprecation string
+		sunset      string
+		wantErr     bool
+	}{
+		{},
+		{deprecation: "2026-10-19"},
+		{sunset: "2027-04-19"},
+		{deprecation: "2026-10-19", sunset: "2027-04-19"},
+		{deprecation: "2027-04-19", sunset: "2026-10-19", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%q %q", tt.deprecation, tt.sunset), func(t *testing.T) {
+			config, err := LoadConfig(writeConfig(t, minimal), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			config.Versioning = Versioning{Deprecation: tt.deprecation, Sunset: tt.sunset}
+
+			err = config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() = %v, want error=%v", err, tt.wantErr)
+			}
+		})
+	}
+}
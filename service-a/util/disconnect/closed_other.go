@@ -0,0 +1,11 @@
+//go:build !unix
+
+package disconnect
+
+import "net"
+
+// closed never reports a closed connection where the socket cannot be peeked
+// at, requests then run until their deadline
+func closed(conn net.Conn) bool {
+	return false
+}
@@ -0,0 +1,40 @@
+//go:build unix
+
+package disconnect
+
+import (
+	"errors"
+	"net"
+	"syscall"
+)
+
+// closed blocks until conn is readable and reports whether its peer closed
+// it. It returns false as soon as the read deadline of conn expires.
+func closed(conn net.Conn) bool {
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return false
+	}
+
+	raw, err := sc.SyscallConn()
+	if err != nil {
+		return false
+	}
+
+	gone := false
+	buf := make([]byte, 1)
+
+	err = raw.Read(func(fd uintptr) bool {
+		n, _, err := syscall.Recvfrom(int(fd), buf, syscall.MSG_PEEK|syscall.MSG_DONTWAIT)
+		if errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.EINTR) {
+			// Nothing to read yet, wait for the poller
+			return false
+		}
+
+		gone = err != nil || n == 0
+
+		return true
+	})
+
+	return err == nil && gone
+}
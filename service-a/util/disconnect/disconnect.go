@@ -0,0 +1,57 @@
+package disconnect
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+)
+
+// ErrClientGone is the cause of a request context cancelled because the
+// client closed its connection
+var ErrClientGone = errors.New("client closed the connection")
+
+// aLongTimeAgo is a read deadline that wakes up a pending read at once
+var aLongTimeAgo = time.Unix(1, 0)
+
+// Watch returns a copy of ctx that is cancelled with ErrClientGone once the
+// client closes conn, and a function that stops watching.
+//
+// The server does not read the connection while a request is handled, so a
+// closed connection goes unnoticed until the response is written. Watch waits
+// for the connection to become readable and peeks at it without consuming
+// anything: an end of file means the client is gone, data means it pipelined
+// another request and is still there. stop must be called before the handler
+// returns, the server reads the connection again afterwards.
+func Watch(ctx context.Context, conn net.Conn) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	if conn == nil {
+		return ctx, func() { cancel(nil) }
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		if closed(conn) {
+			cancel(ErrClientGone)
+		}
+	}()
+
+	stop := func() {
+		// Wake the watcher up, then restore the deadline the server expects
+		_ = conn.SetReadDeadline(aLongTimeAgo)
+		<-done
+		_ = conn.SetReadDeadline(time.Time{})
+
+		cancel(nil)
+	}
+
+	return ctx, stop
+}
+
+// Gone reports whether ctx was cancelled because the client went away
+func Gone(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), ErrClientGone)
+}
@@ -0,0 +1,129 @@
+//go:build unix
+
+package disconnect
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// pair returns both ends of a TCP connection
+func pair(t *testing.T) (server, client net.Conn) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	client, err = net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server, err = ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		server.Close()
+		client.Close()
+	})
+
+	return server, client
+}
+
+func TestWatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		client func(conn net.Conn)
+		gone   bool
+	}{
+		{
+			name:   "client closes the connection",
+			client: func(conn net.Conn) { conn.Close() },
+			gone:   true,
+		},
+		{
+			name:   "client pipelines another request",
+			client: func(conn net.Conn) { conn.Write([]byte("GET / HTTP/1.1\r\n")) },
+			gone:   false,
+		},
+		{
+			name:   "client waits for the response",
+			client: func(conn net.Conn) {},
+			gone:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, client := pair(t)
+
+			ctx, stop := Watch(context.Background(), server)
+
+			tt.client(client)
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(200 * time.Millisecond):
+			}
+
+			if got := Gone(ctx); got != tt.gone {
+				t.Fatalf("Gone() = %v, want %v", got, tt.gone)
+			}
+
+			stop()
+
+			if ctx.Err() == nil {
+				t.Fatal("context still alive after stop")
+			}
+		})
+	}
+}
+
+func TestStopKeepsPipelinedData(t *testing.T) {
+	server, client := pair(t)
+
+	_, stop := Watch(context.Background(), server)
+
+	if _, err := client.Write([]byte("next")); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	stop()
+
+	// The deadline is restored and nothing was consumed
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(server, buf); err != nil {
+		t.Fatalf("read after stop: %v", err)
+	}
+
+	if string(buf) != "next" {
+		t.Fatalf("read %q, want %q", buf, "next")
+	}
+}
+
+func TestStopWhileWaiting(t *testing.T) {
+	server, _ := pair(t)
+
+	_, stop := Watch(context.Background(), server)
+
+	done := make(chan struct{})
+	go func() {
+		stop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("stop did not return")
+	}
+}
@@ -0,0 +1,40 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+// Record is what a store keeps for an idempotency key: the fingerprint of
+// the request that claimed it and, once it completed, its response
+type Record struct {
+	Fingerprint string
+	Completed   bool
+
+	Status      int
+	ContentType string
+	Body        []byte
+
+	// Trace of the original request, replays link to it
+	TraceID string
+	SpanID  string
+}
+
+// Store keeps the idempotency records until their TTL expires.
+// Implementations must be safe for concurrent use; a shared store (e.g.
+// Redis) lets several instances recognize the same key.
+type Store interface {
+	// Claim records key as in progress for the request with the given
+	// fingerprint. When the key is already known it returns the existing
+	// record and false, leaving it untouched.
+	Claim(ctx context.Context, key string, record Record, ttl time.Duration) (*Record, bool, error)
+
+	// Get returns the record of key, nil when absent or expired
+	Get(ctx context.Context, key string) (*Record, error)
+
+	// Complete stores the response of the request that claimed key
+	Complete(ctx context.Context, key string, record Record, ttl time.Duration) error
+
+	// Release forgets key, so that a failed request can be retried with it
+	Release(ctx context.Context, key string) error
+}
@@ -0,0 +1,132 @@
+package idempotency
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrTooLarge is returned by Complete when a response does not fit in the
+// store. The key is released, the request may run again.
+var ErrTooLarge = errors.New("response too large to be recorded")
+
+type entry struct {
+	key     string
+	record  Record
+	expires time.Time
+}
+
+// MemoryStore keeps the idempotency records in process memory, bounded by a
+// number of records and a number of bytes of response bodies. The oldest
+// records are evicted first.
+type MemoryStore struct {
+	maxEntries int
+	maxBytes   int
+
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	records map[string]*list.Element
+}
+
+// NewMemoryStore creates an empty in-memory store holding at most maxEntries
+// records and maxBytes bytes of response bodies
+func NewMemoryStore(maxEntries, maxBytes int) *MemoryStore {
+	return &MemoryStore{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+
+		order:   list.New(),
+		records: make(map[string]*list.Element),
+	}
+}
+
+func (store *MemoryStore) Claim(_ context.Context, key string, record Record, ttl time.Duration) (*Record, bool, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	now := time.Now()
+
+	if element, ok := store.records[key]; ok {
+		e := element.Value.(*entry)
+		if now.Before(e.expires) {
+			existing := e.record
+
+			return &existing, false, nil
+		}
+
+		store.remove(element)
+	}
+
+	store.add(&entry{key: key, record: record, expires: now.Add(ttl)})
+
+	return nil, true, nil
+}
+
+func (store *MemoryStore) Get(_ context.Context, key string) (*Record, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	element, ok := store.records[key]
+	if !ok {
+		return nil, nil
+	}
+
+	e := element.Value.(*entry)
+	if !time.Now().Before(e.expires) {
+		store.remove(element)
+
+		return nil, nil
+	}
+
+	record := e.record
+
+	return &record, nil
+}
+
+func (store *MemoryStore) Complete(_ context.Context, key string, record Record, ttl time.Duration) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if element, ok := store.records[key]; ok {
+		store.remove(element)
+	}
+
+	if len(record.Body) > store.maxBytes {
+		return ErrTooLarge
+	}
+
+	store.add(&entry{key: key, record: record, expires: time.Now().Add(ttl)})
+
+	return nil
+}
+
+func (store *MemoryStore) Release(_ context.Context, key string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if element, ok := store.records[key]; ok {
+		store.remove(element)
+	}
+
+	return nil
+}
+
+// add records e as the newest entry and evicts the oldest ones until the
+// store fits its bounds again
+func (store *MemoryStore) add(e *entry) {
+	store.records[e.key] = store.order.PushBack(e)
+	store.size += len(e.record.Body)
+
+	for len(store.records) > store.maxEntries || store.size > store.maxBytes {
+		store.remove(store.order.Front())
+	}
+}
+
+func (store *MemoryStore) remove(element *list.Element) {
+	e := store.order.Remove(element).(*entry)
+	delete(store.records, e.key)
+	store.size -= len(e.record.Body)
+}
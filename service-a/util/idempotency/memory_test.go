@@ -0,0 +1,201 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var ctx = context.Background()
+
+func completed(body string) Record {
+	return Record{Fingerprint: "f", Completed: true, Status: 200, Body: []byte(body)}
+}
+
+func TestMemoryStoreClaimRace(t *testing.T) {
+	store := NewMemoryStore(100, 1024)
+
+	var claimed atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, ok, err := store.Claim(ctx, "key", Record{Fingerprint: "f"}, time.Minute)
+			if err == nil && ok {
+				claimed.Add(1)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if got := claimed.Load(); got != 1 {
+		t.Fatalf("key claimed %d times, want once", got)
+	}
+}
+
+func TestMemoryStoreLifecycle(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, store *MemoryStore)
+	}{
+		{
+			name: "a claimed key returns the claim",
+			run: func(t *testing.T, store *MemoryStore) {
+				store.Claim(ctx, "key", Record{Fingerprint: "first"}, time.Minute)
+
+				existing, ok, _ := store.Claim(ctx, "key", Record{Fingerprint: "second"}, time.Minute)
+				if ok || existing == nil || existing.Fingerprint != "first" || existing.Completed {
+					t.Fatalf("Claim() = %+v, %v, want the first claim", existing, ok)
+				}
+			},
+		},
+		{
+			name: "a completed key returns the response",
+			run: func(t *testing.T, store *MemoryStore) {
+				store.Claim(ctx, "key", Record{Fingerprint: "f"}, time.Minute)
+				store.Complete(ctx, "key", completed("pong"), time.Minute)
+
+				existing, ok, _ := store.Claim(ctx, "key", Record{Fingerprint: "f"}, time.Minute)
+				if ok || existing == nil || !existing.Completed || string(existing.Body) != "pong" {
+					t.Fatalf("Claim() = %+v, %v, want the completed record", existing, ok)
+				}
+			},
+		},
+		{
+			name: "a released key can be claimed again",
+			run: func(t *testing.T, store *MemoryStore) {
+				store.Claim(ctx, "key", Record{Fingerprint: "f"}, time.Minute)
+				store.Release(ctx, "key")
+
+				if _, ok, _ := store.Claim(ctx, "key", Record{Fingerprint: "f"}, time.Minute); !ok {
+					t.Fatal("released key not claimed again")
+				}
+			},
+		},
+		{
+			name: "an expired claim can be claimed again",
+			run: func(t *testing.T, store *MemoryStore) {
+				store.Claim(ctx, "key", Record{Fingerprint: "f"}, 0)
+
+				if record, _ := store.Get(ctx, "key"); record != nil {
+					t.Fatalf("Get() = %+v, want nil once expired", record)
+				}
+
+				if _, ok, _ := store.Claim(ctx, "key", Record{Fingerprint: "f"}, time.Minute); !ok {
+					t.Fatal("expired key not claimed again")
+				}
+			},
+		},
+		{
+			name: "a response too large is not recorded and releases the key",
+			run: func(t *testing.T, store *MemoryStore) {
+				store.Claim(ctx, "key", Record{Fingerprint: "f"}, time.Minute)
+
+				err := store.Complete(ctx, "key", completed("0123456789"), time.Minute)
+				if !errors.Is(err, ErrTooLarge) {
+					t.Fatalf("Complete() = %v, want ErrTooLarge", err)
+				}
+
+				if record, _ := store.Get(ctx, "key"); record != nil {
+					t.Fatalf("Get() = %+v, want the key released", record)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, NewMemoryStore(10, 8))
+		})
+	}
+}
+
+func TestMemoryStoreBounds(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxEntries int
+		maxBytes   int
+		bodies     []string // completed in order under keys "0", "1", ...
+		kept       []string
+		size       int
+	}{
+		{
+			name:       "oldest keys are evicted above max entries",
+			maxEntries: 2,
+			maxBytes:   100,
+			bodies:     []string{"a", "b", "c"},
+			kept:       []string{"1", "2"},
+			size:       2,
+		},
+		{
+			name:       "oldest keys are evicted above max bytes",
+			maxEntries: 10,
+			maxBytes:   6,
+			bodies:     []string{"aaa", "bbb", "cc"},
+			kept:       []string{"1", "2"},
+			size:       5,
+		},
+		{
+			name:       "a response as large as the store evicts the others",
+			maxEntries: 10,
+			maxBytes:   4,
+			bodies:     []string{"aa", "bbbb"},
+			kept:       []string{"1"},
+			size:       4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryStore(tt.maxEntries, tt.maxBytes)
+
+			for i, body := range tt.bodies {
+				key := string(rune('0' + i))
+
+				store.Claim(ctx, key, Record{Fingerprint: "f"}, time.Minute)
+				if err := store.Complete(ctx, key, completed(body), time.Minute); err != nil {
+					t.Fatalf("Complete(%q): %v", key, err)
+				}
+			}
+
+			for _, key := range tt.kept {
+				if record, _ := store.Get(ctx, key); record == nil {
+					t.Fatalf("key %q evicted, want it kept", key)
+				}
+			}
+
+			if len(store.records) != len(tt.kept) || store.order.Len() != len(tt.kept) {
+				t.Fatalf("%d records, %d in order, want %d", len(store.records), store.order.Len(), len(tt.kept))
+			}
+
+			if store.size != tt.size {
+				t.Fatalf("size = %d, want %d", store.size, tt.size)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreSizeOnReplace(t *testing.T) {
+	store := NewMemoryStore(10, 100)
+
+	store.Claim(ctx, "key", Record{Fingerprint: "f"}, time.Minute)
+	store.Complete(ctx, "key", completed("pong"), time.Minute)
+	store.Complete(ctx, "key", completed("pong pong"), time.Minute)
+
+	if store.size != len("pong pong") {
+		t.Fatalf("size = %d after replacing, want %d", store.size, len("pong pong"))
+	}
+
+	store.Release(ctx, "key")
+
+	if store.size != 0 || store.order.Len() != 0 {
+		t.Fatalf("size = %d, %d in order after release, want empty", store.size, store.order.Len())
+	}
+}
@@ -11,10 +11,10 @@ import (
 func LogWithTrace(ctx context.Context, logger *logrus.Logger) *logrus.Entry {
 	sc := trace.SpanContextFromContext(ctx)
 	if !sc.IsValid() {
-		return logger.WithField("[log_id]", "unknown")
+		return logger.WithContext(ctx).WithField("[log_id]", "unknown")
 	}
 
-	return logger.WithFields(logrus.Fields{
+	return logger.WithContext(ctx).WithFields(logrus.Fields{
 		"[log_id]": sc.TraceID().String(),
 	})
 }
@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Redacted replaces the value of a redacted field
+const Redacted = "[REDACTED]"
+
+// RedactingFormatter wraps a logrus.Formatter and replaces the values of the
+// fields it is given, matched case-insensitively, before they are written
+type RedactingFormatter struct {
+	formatter logrus.Formatter
+
+	mu     sync.RWMutex
+	fields map[string]bool
+}
+
+// NewRedactingFormatter creates a RedactingFormatter around the given formatter
+func NewRedactingFormatter(formatter logrus.Formatter, fields []string) *RedactingFormatter {
+	f := &RedactingFormatter{formatter: formatter}
+	f.SetFields(fields)
+
+	return f
+}
+
+// SetFields replaces the names of the redacted fields
+func (f *RedactingFormatter) SetFields(fields []string) {
+	set := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		set[strings.ToLower(strings.TrimSpace(field))] = true
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.fields = set
+}
+
+// Format implements logrus.Formatter
+func (f *RedactingFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	f.mu.RLock()
+	fields := f.fields
+	f.mu.RUnlock()
+
+	var redacted *logrus.Entry
+	for key := range entry.Data {
+		if !fields[strings.ToLower(key)] {
+			continue
+		}
+
+		// The entry may still be used by the caller, its fields are copied
+		if redacted == nil {
+			redacted = entry.Dup()
+			redacted.Level = entry.Level
+			redacted.Message = entry.Message
+			redacted.Caller = entry.Caller
+			redacted.Buffer = entry.Buffer
+		}
+		redacted.Data[key] = Redacted
+	}
+
+	if redacted == nil {
+		return f.formatter.Format(entry)
+	}
+
+	return f.formatter.Format(redacted)
+}
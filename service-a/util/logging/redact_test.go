@@ -0,0 +1,44 @@
+package logging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestRedactingFormatter(t *testing.T) {
+	var out bytes.Buffer
+
+	logger := logrus.New()
+	logger.SetOutput(&out)
+	f := NewRedactingFormatter(&logrus.TextFormatter{DisableTimestamp: true}, []string{"Authorization", " token "})
+	logger.Formatter = f
+
+	fields := logrus.Fields{"authorization": "Bearer abc", "TOKEN": "xyz", "message": "ping"}
+	logger.WithFields(fields).Info("call")
+
+	line := out.String()
+	for _, leaked := range []string{"Bearer abc", "xyz"} {
+		if strings.Contains(line, leaked) {
+			t.Fatalf("%q logged: %s", leaked, line)
+		}
+	}
+
+	if !strings.Contains(line, "message=ping") || strings.Count(line, Redacted) != 2 {
+		t.Fatalf("want both fields redacted and the others kept: %s", line)
+	}
+
+	if fields["authorization"] != "Bearer abc" {
+		t.Fatal("the fields of the caller were changed")
+	}
+
+	out.Reset()
+	f.SetFields(nil)
+	logger.WithFields(fields).Info("call")
+
+	if !strings.Contains(out.String(), "xyz") {
+		t.Fatalf("field redacted after the rules were cleared: %s", out.String())
+	}
+}
@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// format runs entries through f and returns what it wrote
+func format(t *testing.T, f *SamplingFormatter, entries ...*logrus.Entry) string {
+	t.Helper()
+
+	var out bytes.Buffer
+	for _, entry := range entries {
+		b, err := f.Format(entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out.Write(b)
+	}
+
+	return out.String()
+}
+
+func infoEntry(ctx context.Context, message string, at time.Time) *logrus.Entry {
+	entry := logrus.NewEntry(logrus.New()).WithContext(ctx)
+	entry.Level = logrus.InfoLevel
+	entry.Message = message
+	entry.Time = at
+
+	return entry
+}
+
+func TestSamplingFormatterKeepSampled(t *testing.T) {
+	sampled := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+	}))
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		keepSampled bool
+		want        int // entries written out of 3
+	}{
+		{name: "sampled traces are sampled by default", keepSampled: false, want: 1},
+		{name: "sampled traces are kept when asked", keepSampled: true, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewSamplingFormatter(&logrus.TextFormatter{}, SamplerConfig{
+				Interval:    time.Minute,
+				Limit:       1,
+				KeepSampled: tt.keepSampled,
+			})
+
+			out := format(t, f,
+				infoEntry(sampled, "ping", now),
+				infoEntry(sampled, "ping", now),
+				infoEntry(sampled, "ping", now),
+			)
+
+			if got := bytes.Count([]byte(out), []byte("msg=ping")); got != tt.want {
+				t.Fatalf("%d entries written, want %d:\n%s", got, tt.want, out)
+			}
+		})
+	}
+}
+
+func TestSamplingFormatterReportsEvictedGroups(t *testing.T) {
+	f := NewSamplingFormatter(&logrus.TextFormatter{}, SamplerConfig{Interval: time.Second, Limit: 1})
+	ctx := context.Background()
+	now := time.Now()
+
+	// One group with suppressed entries, and enough others to fill the map
+	format(t, f, infoEntry(ctx, "noisy", now), infoEntry(ctx, "noisy", now), infoEntry(ctx, "noisy", now))
+	for i := 1; i < maxBuckets; i++ {
+		format(t, f, infoEntry(ctx, fmt.Sprintf("quiet %d", i), now))
+	}
+
+	// A new group once the windows are over evicts them all
+	out := format(t, f, infoEntry(ctx, "new", now.Add(time.Second)))
+
+	if !bytes.Contains([]byte(out), []byte(summaryMessage)) || !bytes.Contains([]byte(out), []byte("suppressed=2")) {
+		t.Fatalf("output does not report the evicted suppressed entries:\n%s", out)
+	}
+
+	if len(f.buckets) != 1 {
+		t.Fatalf("%d buckets left, want only the new one", len(f.buckets))
+	}
+}
@@ -0,0 +1,98 @@
+package ratelimit
+
+import (
+	"container/list"
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	key    string
+	tokens float64
+	last   time.Time
+	limit  Limit
+}
+
+// refill adds the tokens earned since the last update, never more than the
+// burst, which a reload may have lowered
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.tokens += elapsed * b.limit.Rate
+		b.last = now
+	}
+
+	b.tokens = math.Min(float64(b.limit.Burst), b.tokens)
+}
+
+// MemoryStore keeps the token buckets in process memory, bounded by a
+// number of buckets. The least recently used bucket is evicted first, its
+// client starts again with a full bucket.
+type MemoryStore struct {
+	maxBuckets int
+
+	mu      sync.Mutex
+	order   *list.List
+	buckets map[string]*list.Element
+}
+
+// NewMemoryStore creates an empty in-memory store holding at most maxBuckets
+// buckets
+func NewMemoryStore(maxBuckets int) *MemoryStore {
+	return &MemoryStore{
+		maxBuckets: maxBuckets,
+
+		order:   list.New(),
+		buckets: make(map[string]*list.Element),
+	}
+}
+
+func (store *MemoryStore) Take(_ context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	var b *bucket
+	if element, ok := store.buckets[key]; ok {
+		store.order.MoveToBack(element)
+		b = element.Value.(*bucket)
+	} else {
+		b = &bucket{key: key, tokens: float64(limit.Burst), last: now}
+		store.add(b)
+	}
+
+	// A reloaded limit applies to existing buckets
+	b.limit = limit
+	b.refill(now)
+
+	result := Result{}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else if limit.Rate > 0 {
+		result.RetryAfter = seconds((1 - b.tokens) / limit.Rate)
+	}
+
+	result.Remaining = int(b.tokens)
+	if limit.Rate > 0 {
+		result.Reset = seconds((float64(limit.Burst) - b.tokens) / limit.Rate)
+	}
+
+	return result, nil
+}
+
+// add records b as the most recently used bucket and evicts the least
+// recently used ones until the store fits its bound again
+func (store *MemoryStore) add(b *bucket) {
+	store.buckets[b.key] = store.order.PushBack(b)
+
+	for len(store.buckets) > store.maxBuckets {
+		evicted := store.order.Remove(store.order.Front()).(*bucket)
+		delete(store.buckets, evicted.key)
+	}
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,150 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreTake(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	limit := Limit{Rate: 2, Burst: 3}
+
+	type take struct {
+		at         time.Duration // since start
+		limit      Limit
+		allowed    bool
+		remaining  int
+		retryAfter time.Duration
+	}
+
+	tests := []struct {
+		name  string
+		takes []take
+	}{
+		{
+			name: "burst is spent at once then refused",
+			takes: []take{
+				{at: 0, limit: limit, allowed: true, remaining: 2},
+				{at: 0, limit: limit, allowed: true, remaining: 1},
+				{at: 0, limit: limit, allowed: true, remaining: 0},
+				{at: 0, limit: limit, allowed: false, remaining: 0, retryAfter: 500 * time.Millisecond},
+			},
+		},
+		{
+			name: "tokens are earned back at the rate",
+			takes: []take{
+				{at: 0, limit: limit, allowed: true, remaining: 2},
+				{at: 0, limit: limit, allowed: true, remaining: 1},
+				{at: 0, limit: limit, allowed: true, remaining: 0},
+				{at: 250 * time.Millisecond, limit: limit, allowed: false, remaining: 0, retryAfter: 250 * time.Millisecond},
+				{at: 500 * time.Millisecond, limit: limit, allowed: true, remaining: 0},
+			},
+		},
+		{
+			name: "refill never exceeds the burst",
+			takes: []take{
+				{at: 0, limit: limit, allowed: true, remaining: 2},
+				{at: time.Hour, limit: limit, allowed: true, remaining: 2},
+			},
+		},
+		{
+			name: "a zero rate never refills",
+			takes: []take{
+				{at: 0, limit: Limit{Burst: 1}, allowed: true, remaining: 0},
+				{at: time.Hour, limit: Limit{Burst: 1}, allowed: false, remaining: 0},
+			},
+		},
+		{
+			name: "a reloaded limit applies to the existing bucket",
+			takes: []take{
+				{at: 0, limit: limit, allowed: true, remaining: 2},
+				{at: 0, limit: Limit{Rate: 2, Burst: 1}, allowed: true, remaining: 0},
+				{at: 0, limit: Limit{Rate: 2, Burst: 1}, allowed: false, remaining: 0, retryAfter: 500 * time.Millisecond},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryStore(16)
+
+			for i, take := range tt.takes {
+				result, err := store.Take(context.Background(), "client", take.limit, start.Add(take.at))
+				if err != nil {
+					t.Fatalf("take %d: %v", i, err)
+				}
+
+				if result.Allowed != take.allowed || result.Remaining != take.remaining || result.RetryAfter != take.retryAfter {
+					t.Fatalf("take %d = %+v, want allowed=%v remaining=%d retry_after=%s",
+						i, result, take.allowed, take.remaining, take.retryAfter)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStoreTakeKeysApart(t *testing.T) {
+	store := NewMemoryStore(16)
+	now := time.Now()
+	limit := Limit{Rate: 1, Burst: 1}
+
+	for _, key := range []string{"a", "b"} {
+		result, _ := store.Take(context.Background(), key, limit, now)
+		if !result.Allowed {
+			t.Fatalf("first take of %q refused", key)
+		}
+	}
+}
+
+func TestMemoryStoreTakeConcurrent(t *testing.T) {
+	store := NewMemoryStore(16)
+	now := time.Now()
+	limit := Limit{Rate: 1, Burst: 10}
+
+	var allowed atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			result, err := store.Take(context.Background(), "client", limit, now)
+			if err == nil && result.Allowed {
+				allowed.Add(1)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if got := allowed.Load(); got != int64(limit.Burst) {
+		t.Fatalf("%d takes allowed, want exactly the burst of %d", got, limit.Burst)
+	}
+}
+
+func TestMemoryStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	store := NewMemoryStore(2)
+	now := time.Now()
+	limit := Limit{Rate: 1, Burst: 1}
+
+	// a and b are spent, a is used again after b, c evicts b
+	for _, key := range []string{"a", "b", "a", "c"} {
+		store.Take(context.Background(), key, limit, now)
+	}
+
+	if len(store.buckets) != 2 || store.order.Len() != 2 {
+		t.Fatalf("%d buckets, %d in order, want 2", len(store.buckets), store.order.Len())
+	}
+
+	if _, ok := store.buckets["b"]; ok {
+		t.Fatal("b kept, want it evicted as the least recently used")
+	}
+
+	if result, _ := store.Take(context.Background(), "a", limit, now); result.Allowed {
+		t.Fatal("a allowed, want its spent bucket kept")
+	}
+}
@@ -0,0 +1,37 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit configures a token bucket: it holds up to Burst tokens and is
+// refilled with Rate tokens per second. Every request takes one token.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Window is the time an empty bucket takes to fill up again
+func (limit Limit) Window() time.Duration {
+	if limit.Rate <= 0 {
+		return 0
+	}
+
+	return time.Duration(float64(limit.Burst) / limit.Rate * float64(time.Second))
+}
+
+// Result describes a bucket after a token was asked for
+type Result struct {
+	Allowed    bool          // Whether a token was taken
+	Remaining  int           // Tokens left in the bucket
+	Reset      time.Duration // Time until the bucket is full again
+	RetryAfter time.Duration // Time until the next token, when not allowed
+}
+
+// Store keeps the token buckets. Implementations must be safe for concurrent
+// use; a shared store (e.g. Redis) lets several instances enforce one limit.
+type Store interface {
+	// Take takes a token from the bucket of key at time now
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
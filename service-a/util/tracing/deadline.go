@@ -0,0 +1,28 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// deadlineProcessor records on every span the time that was left before the
+// deadline of the context the span was started in
+type deadlineProcessor struct{}
+
+func (deadlineProcessor) OnStart(parent context.Context, span sdktrace.ReadWriteSpan) {
+	deadline, ok := parent.Deadline()
+	if !ok {
+		return
+	}
+
+	span.SetAttributes(attribute.Int64("deadline.remaining_ms", time.Until(deadline).Milliseconds()))
+}
+
+func (deadlineProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+func (deadlineProcessor) Shutdown(context.Context) error { return nil }
+
+func (deadlineProcessor) ForceFlush(context.Context) error { return nil }
@@ -0,0 +1,51 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// monitoredExporter remembers the outcome of the last export
+type monitoredExporter struct {
+	sdktrace.SpanExporter
+
+	mu      sync.Mutex
+	lastErr error
+	lastAt  time.Time
+}
+
+// exporter is the span exporter of the tracer provider set up by InitTracer
+var exporter *monitoredExporter
+
+func (e *monitoredExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.lastErr = err
+	e.lastAt = time.Now()
+
+	return err
+}
+
+// ExporterStatus returns the error of the last span export, or nil if it succeeded
+func ExporterStatus() error {
+	if exporter == nil {
+		return errors.New("tracer is not initialized")
+	}
+
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+
+	if exporter.lastErr != nil {
+		return fmt.Errorf("last export at %s failed: %w", exporter.lastAt.Format(time.RFC3339), exporter.lastErr)
+	}
+
+	return nil
+}
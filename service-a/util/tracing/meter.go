@@ -0,0 +1,57 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// InitMeter initializes the OpenTelemetry meter provider. Metrics are sent to
+// the same collector as the traces, every interval.
+func InitMeter(serviceName, otlpEndpoint, otlpHeaders string, interval time.Duration) (func(context.Context) error, error) {
+	ctx := context.Background()
+
+	// Create resource with service information
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceName(serviceName),
+			semconv.ServiceVersion("1.0.0"),
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create OTLP metric exporter
+	metricExporter, err := otlpmetricgrpc.New(ctx,
+		otlpmetricgrpc.WithInsecure(),
+		otlpmetricgrpc.WithEndpoint(otlpEndpoint),
+		otlpmetricgrpc.WithHeaders(parseHeaders(otlpHeaders)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create meter provider
+	meterProvider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter, sdkmetric.WithInterval(interval))),
+		sdkmetric.WithResource(res),
+	)
+
+	// Set global meter provider
+	otel.SetMeterProvider(meterProvider)
+
+	// Return cleanup function
+	return meterProvider.Shutdown, nil
+}
+
+// GetMeter returns a meter for the given name
+func GetMeter(name string) metric.Meter {
+	return otel.Meter(name)
+}
@@ -0,0 +1,40 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"service-a/util/logging"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RecordPanic records a recovered panic value as an exception event with the
+// stack of the panicking goroutine on the span of ctx, marks the span as
+// failed and logs it. It must be called from the deferred function that
+// recovered, wherever work runs outside the handler chain: stream writers,
+// WebSocket sessions or goroutines of the service layer.
+func RecordPanic(ctx context.Context, logger *logrus.Logger, value any) {
+	const op = "tracing.RecordPanic"
+
+	message := fmt.Sprint(value)
+	stack := string(debug.Stack())
+
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent(semconv.ExceptionEventName, trace.WithAttributes(
+		semconv.ExceptionType(fmt.Sprintf("%T", value)),
+		semconv.ExceptionMessage(message),
+		semconv.ExceptionStacktrace(stack),
+	))
+	span.SetStatus(codes.Error, "panic: "+message)
+
+	logging.LogWithTrace(ctx, logger).WithFields(logrus.Fields{
+		"[op]":  op,
+		"panic": message,
+		"stack": stack,
+	}).Error("recovered from panic")
+}
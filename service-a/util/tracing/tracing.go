@@ -2,9 +2,11 @@ package tracing
 
 import (
 	"context"
+	"strings"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
@@ -12,7 +14,7 @@ import (
 )
 
 // InitTracer initializes the OpenTelemetry tracer
-func InitTracer(serviceName, otlpEndpoint string) (func(context.Context) error, error) {
+func InitTracer(serviceName, otlpEndpoint, otlpHeaders string, sampler sdktrace.Sampler) (func(context.Context) error, error) {
 	ctx := context.Background()
 
 	// Create resource with service information
@@ -30,25 +32,52 @@ func InitTracer(serviceName, otlpEndpoint string) (func(context.Context) error,
 	traceExporter, err := otlptracegrpc.New(ctx,
 		otlptracegrpc.WithInsecure(),
 		otlptracegrpc.WithEndpoint(otlpEndpoint),
+		otlptracegrpc.WithHeaders(parseHeaders(otlpHeaders)),
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	// Keep track of export failures for readiness checks
+	exporter = &monitoredExporter{SpanExporter: traceExporter}
+
 	// Create trace provider
 	tracerProvider := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(traceExporter),
+		sdktrace.WithSpanProcessor(deadlineProcessor{}),
+		sdktrace.WithBatcher(exporter),
 		sdktrace.WithResource(res),
-		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithSampler(sampler),
 	)
 
 	// Set global tracer provider
 	otel.SetTracerProvider(tracerProvider)
 
+	// Set global propagator for W3C trace context and baggage
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
 	// Return cleanup function
 	return tracerProvider.Shutdown, nil
 }
 
+// parseHeaders parses "key=value,key2=value2" into a header map
+func parseHeaders(s string) map[string]string {
+	headers := make(map[string]string)
+
+	for _, pair := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			continue
+		}
+
+		headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	return headers
+}
+
 // GetTracer returns a tracer for the given name
 func GetTracer(name string) trace.Tracer {
 	return otel.Tracer(name)
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"service-b/api/pb"
+	"service-b/service"
+	"service-b/util/logging"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Chat answers every message of the stream with a pong.
+//
+// The stream span lasts as long as the session. Every exchange gets its own
+// span which continues the trace sent along with the message, or starts a new
+// one, and is linked to the stream span, so a long session does not turn into
+// a single giant trace.
+func (api *Api) Chat(stream pb.BService_ChatServer) error {
+	const op = "api.Api.Chat"
+
+	// Start span
+	ctx, span := api.tracer.Start(stream.Context(), op)
+	defer span.End()
+
+	// Get logger with trace id
+	logger := logging.LogWithTrace(ctx, api.logger)
+
+	logger.WithFields(logrus.Fields{
+		"[op]": op,
+	}).Info("chat session opened")
+
+	span.SetAttributes(
+		attribute.String("api.operation", "chat"),
+	)
+
+	exchanged := 0
+	for {
+		request, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// Record the error in the span
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+
+			return grpcError(ctx, err)
+		}
+
+		err = api.chatExchange(ctx, stream, request)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+
+			return grpcError(ctx, err)
+		}
+
+		exchanged++
+	}
+
+	logger.WithFields(logrus.Fields{
+		"[op]":      op,
+		"exchanged": exchanged,
+	}).Info("chat session closed")
+
+	// Record success attributes
+	span.SetAttributes(
+		attribute.Int("api.output.exchanged", exchanged),
+	)
+	span.SetStatus(codes.Ok, "request completed successfully")
+
+	return nil
+}
+
+// chatExchange answers a single chat message. A failure to answer is sent
+// back as an error status in the response stream's trailer, ending the stream.
+func (api *Api) chatExchange(sessionCtx context.Context, stream pb.BService_ChatServer, request *pb.ChatRequest) error {
+	const op = "api.Api.Chat.message"
+
+	opts := []trace.SpanStartOption{
+		trace.WithLinks(trace.LinkFromContext(sessionCtx, attribute.String("link.type", "chat.session"))),
+	}
+
+	ctx := otel.GetTextMapPropagator().Extract(sessionCtx, propagation.MapCarrier(request.GetTraceContext()))
+	if !trace.SpanContextFromContext(ctx).IsRemote() {
+		opts = append(opts, trace.WithNewRoot())
+	}
+
+	// Start span
+	ctx, span := api.tracer.Start(ctx, op, opts...)
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("api.operation", "chat.message"),
+		attribute.Int64("api.chat.sequence", request.GetSequence()),
+		attribute.String("api.input.message", request.GetMessage()),
+	)
+
+	params := &service.ChatParams{
+		Message: request.GetMessage(),
+	}
+
+	result, err := api.service.Chat(ctx, params)
+	if err != nil {
+		// Record the error in the span
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return err
+	}
+
+	response := &pb.ChatResponse{
+		PongMessage: result.PongMessage,
+		Sequence:    request.GetSequence(),
+	}
+
+	err = stream.Send(response)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return err
+	}
+
+	// Record success attributes
+	span.SetAttributes(
+		attribute.String("api.output.response", fmt.Sprintf("%+v", response)),
+	)
+	span.SetStatus(codes.Ok, "request completed successfully")
+
+	return nil
+}
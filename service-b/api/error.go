@@ -0,0 +1,18 @@
+package api
+
+import (
+	"context"
+
+	"google.golang.org/grpc/status"
+)
+
+// grpcError returns the error to send back for err. When the call was
+// cancelled or ran out of time, the matching Canceled or DeadlineExceeded
+// status is returned instead of the error it caused in the lower layers.
+func grpcError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return status.FromContextError(ctxErr).Err()
+	}
+
+	return err
+}
@@ -109,6 +109,230 @@ func (x *PingResponse) GetPongMessage() string {
 	return ""
 }
 
+type PingStreamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PingMessage   string                 `protobuf:"bytes,1,opt,name=ping_message,json=pingMessage,proto3" json:"ping_message,omitempty"`
+	Count         int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`                             // Number of messages, 1 to 100
+	IntervalMs    int32                  `protobuf:"varint,3,opt,name=interval_ms,json=intervalMs,proto3" json:"interval_ms,omitempty"` // Delay between two messages, 0 to 10000
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PingStreamRequest) Reset() {
+	*x = PingStreamRequest{}
+	mi := &file_b_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PingStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingStreamRequest) ProtoMessage() {}
+
+func (x *PingStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_b_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PingStreamRequest.ProtoReflect.Descriptor instead.
+func (*PingStreamRequest) Descriptor() ([]byte, []int) {
+	return file_b_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PingStreamRequest) GetPingMessage() string {
+	if x != nil {
+		return x.PingMessage
+	}
+	return ""
+}
+
+func (x *PingStreamRequest) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *PingStreamRequest) GetIntervalMs() int32 {
+	if x != nil {
+		return x.IntervalMs
+	}
+	return 0
+}
+
+type PingStreamResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PongMessage   string                 `protobuf:"bytes,1,opt,name=pong_message,json=pongMessage,proto3" json:"pong_message,omitempty"`
+	Sequence      int32                  `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"` // Position of the message in the stream, starting at 1
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PingStreamResponse) Reset() {
+	*x = PingStreamResponse{}
+	mi := &file_b_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PingStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingStreamResponse) ProtoMessage() {}
+
+func (x *PingStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_b_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PingStreamResponse.ProtoReflect.Descriptor instead.
+func (*PingStreamResponse) Descriptor() ([]byte, []int) {
+	return file_b_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PingStreamResponse) GetPongMessage() string {
+	if x != nil {
+		return x.PongMessage
+	}
+	return ""
+}
+
+func (x *PingStreamResponse) GetSequence() int32 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+type ChatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Sequence      int64                  `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`                                                                                                      // Set by the sender, echoed in the response
+	TraceContext  map[string]string      `protobuf:"bytes,3,rep,name=trace_context,json=traceContext,proto3" json:"trace_context,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Propagated context of the exchange (traceparent, ...)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatRequest) Reset() {
+	*x = ChatRequest{}
+	mi := &file_b_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatRequest) ProtoMessage() {}
+
+func (x *ChatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_b_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatRequest.ProtoReflect.Descriptor instead.
+func (*ChatRequest) Descriptor() ([]byte, []int) {
+	return file_b_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ChatRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ChatRequest) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *ChatRequest) GetTraceContext() map[string]string {
+	if x != nil {
+		return x.TraceContext
+	}
+	return nil
+}
+
+type ChatResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PongMessage   string                 `protobuf:"bytes,1,opt,name=pong_message,json=pongMessage,proto3" json:"pong_message,omitempty"`
+	Sequence      int64                  `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatResponse) Reset() {
+	*x = ChatResponse{}
+	mi := &file_b_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatResponse) ProtoMessage() {}
+
+func (x *ChatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_b_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatResponse.ProtoReflect.Descriptor instead.
+func (*ChatResponse) Descriptor() ([]byte, []int) {
+	return file_b_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ChatResponse) GetPongMessage() string {
+	if x != nil {
+		return x.PongMessage
+	}
+	return ""
+}
+
+func (x *ChatResponse) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
 var File_b_proto protoreflect.FileDescriptor
 
 const file_b_proto_rawDesc = "" +
@@ -117,9 +341,30 @@ const file_b_proto_rawDesc = "" +
 	"\vPingRequest\x12!\n" +
 	"\fping_message\x18\x01 \x01(\tR\vpingMessage\"1\n" +
 	"\fPingResponse\x12!\n" +
-	"\fpong_message\x18\x01 \x01(\tR\vpongMessage27\n" +
+	"\fpong_message\x18\x01 \x01(\tR\vpongMessage\"m\n" +
+	"\x11PingStreamRequest\x12!\n" +
+	"\fping_message\x18\x01 \x01(\tR\vpingMessage\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\x12\x1f\n" +
+	"\vinterval_ms\x18\x03 \x01(\x05R\n" +
+	"intervalMs\"S\n" +
+	"\x12PingStreamResponse\x12!\n" +
+	"\fpong_message\x18\x01 \x01(\tR\vpongMessage\x12\x1a\n" +
+	"\bsequence\x18\x02 \x01(\x05R\bsequence\"\xcc\x01\n" +
+	"\vChatRequest\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x1a\n" +
+	"\bsequence\x18\x02 \x01(\x03R\bsequence\x12F\n" +
+	"\rtrace_context\x18\x03 \x03(\v2!.pb.ChatRequest.TraceContextEntryR\ftraceContext\x1a?\n" +
+	"\x11TraceContextEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"M\n" +
+	"\fChatResponse\x12!\n" +
+	"\fpong_message\x18\x01 \x01(\tR\vpongMessage\x12\x1a\n" +
+	"\bsequence\x18\x02 \x01(\x03R\bsequence2\xa9\x01\n" +
 	"\bBService\x12+\n" +
-	"\x04Ping\x12\x0f.pb.PingRequest\x1a\x10.pb.PingResponse\"\x00B\x06Z\x04./pbb\x06proto3"
+	"\x04Ping\x12\x0f.pb.PingRequest\x1a\x10.pb.PingResponse\"\x00\x12?\n" +
+	"\n" +
+	"PingStream\x12\x15.pb.PingStreamRequest\x1a\x16.pb.PingStreamResponse\"\x000\x01\x12/\n" +
+	"\x04Chat\x12\x0f.pb.ChatRequest\x1a\x10.pb.ChatResponse\"\x00(\x010\x01B\x06Z\x04./pbb\x06proto3"
 
 var (
 	file_b_proto_rawDescOnce sync.Once
@@ -133,19 +378,29 @@ func file_b_proto_rawDescGZIP() []byte {
 	return file_b_proto_rawDescData
 }
 
-var file_b_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_b_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_b_proto_goTypes = []any{
-	(*PingRequest)(nil),  // 0: pb.PingRequest
-	(*PingResponse)(nil), // 1: pb.PingResponse
+	(*PingRequest)(nil),        // 0: pb.PingRequest
+	(*PingResponse)(nil),       // 1: pb.PingResponse
+	(*PingStreamRequest)(nil),  // 2: pb.PingStreamRequest
+	(*PingStreamResponse)(nil), // 3: pb.PingStreamResponse
+	(*ChatRequest)(nil),        // 4: pb.ChatRequest
+	(*ChatResponse)(nil),       // 5: pb.ChatResponse
+	nil,                        // 6: pb.ChatRequest.TraceContextEntry
 }
 var file_b_proto_depIdxs = []int32{
-	0, // 0: pb.BService.Ping:input_type -> pb.PingRequest
-	1, // 1: pb.BService.Ping:output_type -> pb.PingResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	6, // 0: pb.ChatRequest.trace_context:type_name -> pb.ChatRequest.TraceContextEntry
+	0, // 1: pb.BService.Ping:input_type -> pb.PingRequest
+	2, // 2: pb.BService.PingStream:input_type -> pb.PingStreamRequest
+	4, // 3: pb.BService.Chat:input_type -> pb.ChatRequest
+	1, // 4: pb.BService.Ping:output_type -> pb.PingResponse
+	3, // 5: pb.BService.PingStream:output_type -> pb.PingStreamResponse
+	5, // 6: pb.BService.Chat:output_type -> pb.ChatResponse
+	4, // [4:7] is the sub-list for method output_type
+	1, // [1:4] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_b_proto_init() }
@@ -159,7 +414,7 @@ func file_b_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_b_proto_rawDesc), len(file_b_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -8,6 +8,7 @@ import (
 	"service-b/api/pb"
 	"service-b/service"
 	"service-b/util/logging"
+	"service-b/util/wait"
 
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel/attribute"
@@ -36,7 +37,14 @@ func (api *Api) Ping(ctx context.Context, request *pb.PingRequest) (*pb.PingResp
 	)
 
 	// Simulate a validation operation
-	time.Sleep(250 * time.Millisecond)
+	err := wait.Sleep(ctx, 250*time.Millisecond)
+	if err != nil {
+		// Record the error in the span
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, grpcError(ctx, err)
+	}
 
 	params := &service.PingParams{
 		PingMessage: request.GetPingMessage(),
@@ -48,7 +56,7 @@ func (api *Api) Ping(ctx context.Context, request *pb.PingRequest) (*pb.PingResp
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 
-		return nil, err
+		return nil, grpcError(ctx, err)
 	}
 
 	response := &pb.PingResponse{
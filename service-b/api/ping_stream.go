@@ -0,0 +1,105 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"service-b/api/pb"
+	"service-b/service"
+	"service-b/util/logging"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	maxStreamCount    = 100
+	maxStreamInterval = 10 * time.Second
+)
+
+func (api *Api) PingStream(request *pb.PingStreamRequest, stream pb.BService_PingStreamServer) error {
+	const op = "api.Api.PingStream"
+
+	// Start span
+	ctx, span := api.tracer.Start(stream.Context(), op)
+	defer span.End()
+
+	// Get logger with trace id
+	logger := logging.LogWithTrace(ctx, api.logger)
+
+	logger.WithFields(logrus.Fields{
+		"[op]":    op,
+		"request": request,
+	}).Info()
+
+	// Add attributes to the span
+	span.SetAttributes(
+		attribute.String("api.operation", "ping_stream"),
+		attribute.String("api.input.request", fmt.Sprintf("%+v", request)),
+	)
+
+	interval := time.Duration(request.GetIntervalMs()) * time.Millisecond
+
+	if request.GetCount() < 1 || request.GetCount() > maxStreamCount {
+		err := status.Errorf(grpccodes.InvalidArgument, "count must be between 1 and %d, got %d", maxStreamCount, request.GetCount())
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return err
+	}
+
+	if interval < 0 || interval > maxStreamInterval {
+		err := status.Errorf(grpccodes.InvalidArgument, "interval_ms must be between 0 and %d, got %d", maxStreamInterval.Milliseconds(), request.GetIntervalMs())
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return err
+	}
+
+	params := &service.PingStreamParams{
+		PingMessage: request.GetPingMessage(),
+		Count:       int(request.GetCount()),
+		Interval:    interval,
+	}
+
+	sent := 0
+	err := api.service.PingStream(ctx, params, func(result *service.PingStreamResult) error {
+		response := &pb.PingStreamResponse{
+			PongMessage: result.PongMessage,
+			Sequence:    int32(result.Sequence),
+		}
+
+		err := stream.Send(response)
+		if err != nil {
+			return err
+		}
+
+		sent++
+		span.AddEvent("message.sent", trace.WithAttributes(
+			attribute.Int("api.stream.sequence", result.Sequence),
+		))
+
+		return nil
+	})
+	if err != nil {
+		// Record the error in the span
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return grpcError(ctx, err)
+	}
+
+	// Record success attributes
+	span.SetAttributes(
+		attribute.Int("api.output.sent", sent),
+	)
+	span.SetStatus(codes.Ok, "request completed successfully")
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+
+	"service-b/util/config"
+	"service-b/util/logging"
+	"service-b/util/tracing"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// watchConfig starts hot reloading the config file and applies the live
+// logging and tracing settings whenever it changes
+func watchConfig(
+	ctx context.Context,
+	current config.Config,
+	logger *logrus.Logger,
+	tracer trace.Tracer,
+	redactingFormatter *logging.RedactingFormatter,
+	samplingFormatter *logging.SamplingFormatter,
+	sampler *tracing.RatioSampler,
+) *config.Watcher {
+	const op = "main.watchConfig"
+
+	watcher := config.NewWatcher(configFile, configEnv, current, logger, tracer)
+
+	watcher.Subscribe(func(config config.Config) {
+		level, _ := logrus.ParseLevel(config.Logging.Level)
+		logger.SetLevel(level)
+
+		redactingFormatter.SetFields(config.Logging.Redact)
+		samplingFormatter.SetConfig(logging.SamplerConfig{
+			Interval:    config.Logging.Sampling.Interval,
+			Limit:       config.Logging.Sampling.Limit,
+			KeepSampled: config.Logging.Sampling.KeepSampled,
+		})
+
+		sampler.SetRatio(config.OtelTracer.SampleRatio)
+	})
+
+	go func() {
+		if err := watcher.Run(ctx); err != nil {
+			logger.WithFields(logrus.Fields{
+				"[op]":  op,
+				"scope": "WatchConfig",
+				"err":   err.Error(),
+			}).Error()
+		}
+	}()
+
+	return watcher
+}
@@ -1,53 +1,74 @@
 package main
 
 import (
-	"fmt"
 	"log"
-	"net"
 	"os"
 
 	"service-b/api"
 	"service-b/api/pb"
+	"service-b/interceptor"
+	"service-b/util/auth"
+	"service-b/util/listener"
 
+	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
 	"go.opentelemetry.io/otel/propagation"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 )
 
-func runGrpcServer(port int, server *api.Api) *grpc.Server {
+func runGrpcServer(host string, port int, server *api.Api, verifier *auth.Verifier, authRequired bool, logger *logrus.Logger) (*grpc.Server, *health.Server) {
 	// Create new gRPC server
 	opts := []grpc.ServerOption{
 		grpc.StatsHandler(otelgrpc.NewServerHandler(
 			otelgrpc.WithPropagators(propagation.TraceContext{}),
+			otelgrpc.WithFilter(filters.Not(filters.HealthCheck())),
 		)),
+		grpc.ChainUnaryInterceptor(
+			interceptor.AccessLogUnary(logger),
+			interceptor.RecoverUnary(logger),
+			interceptor.AuthUnary(verifier, authRequired, logger),
+		),
+		grpc.ChainStreamInterceptor(
+			interceptor.AccessLogStream(logger),
+			interceptor.RecoverStream(logger),
+			interceptor.AuthStream(verifier, authRequired, logger),
+		),
 	}
 	grpcServer := grpc.NewServer(opts...)
 
 	// Register gRPC services
 	pb.RegisterBServiceServer(grpcServer, server)
 
+	// Register the standard health service, used by clients' readiness checks
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
 	// Register reflection service on gRPC server.
 	reflection.Register(grpcServer)
 
-	// Listen at specified port
-	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	// Listen at configured address
+	ln, err := listener.Listen(host, port)
 	if err != nil {
-		log.Printf("failed to listen at port: %v!", port)
+		log.Printf("failed to listen at %s port %d: %v", host, port, err)
 
 		os.Exit(1)
 	}
 
-	log.Printf("listening at port: %d", port)
+	log.Printf("listening at: %s", ln.Addr())
 
 	// Serve the gRPC server
 	go func() {
 		log.Printf("gRPC server started successfully 🚀")
 
-		if err := grpcServer.Serve(listener); err != nil {
+		if err := grpcServer.Serve(ln); err != nil {
 			log.Printf("failed to serve: %v", err)
 		}
 	}()
 
-	return grpcServer
+	return grpcServer, healthServer
 }
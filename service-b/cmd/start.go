@@ -6,11 +6,15 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"syscall"
+	"time"
 
 	"service-b/api"
 	"service-b/service"
 	"service-b/store"
+	"service-b/util/auth"
 	"service-b/util/config"
+	"service-b/util/logging"
 	"service-b/util/tracing"
 
 	"github.com/sirupsen/logrus"
@@ -29,7 +33,7 @@ func start() {
 	logger.Out = os.Stdout
 
 	// --- Load config ---
-	config, err := config.LoadConfig(".")
+	config, err := config.LoadConfig(configFile, configEnv)
 	if err != nil {
 		logger.WithFields(logrus.Fields{
 			"[op]":  op,
@@ -40,8 +44,23 @@ func start() {
 		os.Exit(1)
 	}
 
+	// --- Apply log level ---
+	level, _ := logrus.ParseLevel(config.Logging.Level)
+	logger.Level = level
+
+	// --- Enable log redaction and sampling ---
+	redactingFormatter := logging.NewRedactingFormatter(logger.Formatter, config.Logging.Redact)
+	samplingFormatter := logging.NewSamplingFormatter(redactingFormatter, logging.SamplerConfig{
+		Interval:    config.Logging.Sampling.Interval,
+		Limit:       config.Logging.Sampling.Limit,
+		KeepSampled: config.Logging.Sampling.KeepSampled,
+	})
+	logger.Formatter = samplingFormatter
+
 	// --- Init otel tracer ---
-	cleanup, err := tracing.InitTracer(config.OtelTracer.Name, config.OtelTracer.Endpoint)
+	sampler := tracing.NewRatioSampler(config.OtelTracer.SampleRatio)
+
+	cleanup, err := tracing.InitTracer(config.OtelTracer.Name, config.OtelTracer.Endpoint, config.OtelTracer.Headers, sampler)
 	if err != nil {
 		logger.WithFields(logrus.Fields{
 			"[op]":  op,
@@ -49,16 +68,6 @@ func start() {
 			"err":   err.Error(),
 		}).Error()
 	}
-	defer func() {
-		if err := cleanup(context.Background()); err != nil {
-			logger.WithFields(logrus.Fields{
-				"[op]":  op,
-				"scope": "CleanupTracer",
-				"err":   err.Error(),
-			}).Error()
-		}
-	}()
-
 	tracer := tracing.GetTracer(config.OtelTracer.Name)
 
 	logger.WithFields(logrus.Fields{
@@ -66,6 +75,16 @@ func start() {
 		"config": fmt.Sprintf("%+v", config),
 	}).Infof("Starting '%s' service ...", config.App.Name)
 
+	// --- Cancel on SIGINT or SIGTERM ---
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// --- Report suppressed log entries of quiet groups ---
+	go samplingFormatter.Run(ctx)
+
+	// --- Watch config for changes ---
+	watchConfig(ctx, config, logger, tracer, redactingFormatter, samplingFormatter, sampler)
+
 	// --- Init store layer ---
 	store := store.NewStore(logger, tracer)
 
@@ -76,14 +95,58 @@ func start() {
 	restApi := api.NewApi(logger, tracer, service)
 
 	// --- Run servers ---
-	runGrpcServer(config.App.Port, restApi)
+	grpcServer, healthServer := runGrpcServer(config.App.Host, config.App.Port, restApi, auth.NewVerifier(config.Auth), config.Auth.Required, logger)
+
+	// --- Block until SIGINT or SIGTERM is received ---
+	<-ctx.Done()
+	stop()
+
+	logger.WithFields(logrus.Fields{
+		"[op]":    op,
+		"timeout": config.App.ShutdownTimeout.String(),
+	}).Info("Shutting down, draining in-flight calls ...")
+
+	// --- Drain and flush share the same deadline ---
+	deadline := time.Now().Add(config.App.ShutdownTimeout)
 
-	// --- Wait for ctrl + c to exit ---
-	ch := make(chan os.Signal, 1)
-	signal.Notify(ch, os.Interrupt)
+	// --- Report NOT_SERVING so that clients stop routing calls here ---
+	healthServer.Shutdown()
 
-	// --- Block until a signal is received ---
-	<-ch
+	// --- Stop accepting calls and drain in-flight ones ---
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(config.App.ShutdownTimeout):
+		logger.WithFields(logrus.Fields{
+			"[op]":  op,
+			"scope": "ShutdownGrpcServer",
+			"err":   "drain timed out, cancelling remaining calls",
+		}).Error()
+
+		grpcServer.Stop()
+	}
+
+	// --- Report the log entries still suppressed ---
+	samplingFormatter.Flush()
+
+	// --- Flush telemetry ---
+	if cleanup != nil {
+		flushCtx, cancel := context.WithDeadline(context.Background(), deadline)
+		defer cancel()
+
+		if err := cleanup(flushCtx); err != nil {
+			logger.WithFields(logrus.Fields{
+				"[op]":  op,
+				"scope": "CleanupTracer",
+				"err":   err.Error(),
+			}).Error()
+		}
+	}
 
 	log.Printf("end of program...")
 }
@@ -0,0 +1,107 @@
+package interceptor
+
+import (
+	"context"
+	"time"
+
+	"service-b/util/logging"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// AccessLogUnary creates a unary interceptor that writes one summary line per call
+func AccessLogUnary(logger *logrus.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		bytes := 0
+		if msg, ok := resp.(proto.Message); ok && err == nil {
+			bytes = proto.Size(msg)
+		}
+
+		logAccess(ctx, logger, "unary", info.FullMethod, err, time.Since(start), bytes)
+
+		return resp, err
+	}
+}
+
+// AccessLogStream creates a stream interceptor that writes one summary line per stream
+func AccessLogStream(logger *logrus.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+
+		counted := &countingStream{ServerStream: ss}
+
+		err := handler(srv, counted)
+
+		logAccess(ss.Context(), logger, "stream", info.FullMethod, err, time.Since(start), counted.bytes)
+
+		return err
+	}
+}
+
+// countingStream counts the bytes of the messages sent on a stream
+type countingStream struct {
+	grpc.ServerStream
+
+	bytes int
+}
+
+func (s *countingStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if msg, ok := m.(proto.Message); ok && err == nil {
+		s.bytes += proto.Size(msg)
+	}
+
+	return err
+}
+
+func logAccess(ctx context.Context, logger *logrus.Logger, kind, fullMethod string, err error, latency time.Duration, bytes int) {
+	const op = "interceptor.AccessLog"
+
+	code := status.Code(err)
+	sc := trace.SpanContextFromContext(ctx)
+
+	peerAddr := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		peerAddr = p.Addr.String()
+	}
+
+	userAgent := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("user-agent"); len(values) > 0 {
+			userAgent = values[0]
+		}
+	}
+
+	entry := logging.LogWithTrace(ctx, logger).WithFields(logrus.Fields{
+		"[op]":        op,
+		"kind":        kind,
+		"full_method": fullMethod,
+		"code":        code.String(),
+		"latency":     latency.String(),
+		"bytes":       bytes,
+		"peer":        peerAddr,
+		"user_agent":  userAgent,
+		"trace_id":    sc.TraceID().String(),
+		"span_id":     sc.SpanID().String(),
+	})
+
+	switch code {
+	case codes.OK:
+		entry.Info("call completed")
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable, codes.Unimplemented:
+		entry.Error("call completed")
+	default:
+		entry.Warn("call completed")
+	}
+}
@@ -0,0 +1,94 @@
+package interceptor
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"service-b/util/auth"
+	"service-b/util/config"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const testSecret = "fwd"
+
+// forwardedToken signs a token the way service-a forwards its principals
+func forwardedToken(t *testing.T, audience string) string {
+	t.Helper()
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"sub": "alice",
+		"iss": "service-a",
+		"aud": audience,
+		"iat": now.Unix(),
+		"exp": now.Add(time.Minute).Unix(),
+		"amr": []string{"api_key"},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+func TestAuthUnary(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	tests := []struct {
+		name          string
+		secret        string
+		method        string
+		authorization string
+		code          codes.Code
+		principal     string
+	}{
+		{name: "health check without token", secret: testSecret, method: "/grpc.health.v1.Health/Check", code: codes.OK},
+		{name: "health watch without token", secret: testSecret, method: "/grpc.health.v1.Health/Watch", code: codes.OK},
+		{name: "reflection without token", secret: testSecret, method: "/grpc.reflection.v1.ServerReflection/ServerReflectionInfo", code: codes.OK},
+		{name: "v1alpha reflection without token", secret: testSecret, method: "/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo", code: codes.OK},
+		{name: "health check with verification off", method: "/grpc.health.v1.Health/Check", code: codes.OK},
+		{name: "health check with an invalid token", secret: testSecret, method: "/grpc.health.v1.Health/Check", authorization: "Bearer junk", code: codes.OK},
+		{name: "ping without token", secret: testSecret, method: "/pb.BService/Ping", code: codes.Unauthenticated},
+		{name: "ping with verification off", method: "/pb.BService/Ping", code: codes.Unauthenticated},
+		{name: "method named like an exempt service", secret: testSecret, method: "/grpc.health.v1.HealthX/Check", code: codes.Unauthenticated},
+		{name: "ping with an invalid token", secret: testSecret, method: "/pb.BService/Ping", authorization: "Bearer junk", code: codes.Unauthenticated},
+		{name: "ping with a token for another audience", secret: testSecret, method: "/pb.BService/Ping", authorization: "Bearer " + forwardedToken(t, "service-c"), code: codes.Unauthenticated},
+		{name: "ping with a forwarded token", secret: testSecret, method: "/pb.BService/Ping", authorization: "Bearer " + forwardedToken(t, "service-b"), code: codes.OK, principal: "alice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := auth.NewVerifier(config.Auth{Secret: tt.secret, Issuer: "service-a", Audience: "service-b", Leeway: time.Second})
+			interceptor := AuthUnary(verifier, true, logger)
+
+			ctx := context.Background()
+			if tt.authorization != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.authorization))
+			}
+
+			principal := ""
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, req any) (any, error) {
+				if p, ok := auth.PrincipalFromContext(ctx); ok {
+					principal = p.Subject
+				}
+
+				return nil, nil
+			})
+
+			if status.Code(err) != tt.code || principal != tt.principal {
+				t.Fatalf("got %v with principal %q, want %s with %q", err, principal, tt.code, tt.principal)
+			}
+		})
+	}
+}
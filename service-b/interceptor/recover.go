@@ -0,0 +1,73 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"service-b/util/logging"
+
+	"github.com/sirupsen/logrus"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RecoverUnary creates a unary interceptor that turns a panic of the handler
+// into an Internal error. The panic is recorded on the call span and logged
+// with its stack, the panic value never reaches the client.
+func RecoverUnary(logger *logrus.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if value := recover(); value != nil {
+				err = recordPanic(ctx, logger, info.FullMethod, value)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// RecoverStream is the stream counterpart of RecoverUnary
+func RecoverStream(logger *logrus.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if value := recover(); value != nil {
+				err = recordPanic(ss.Context(), logger, info.FullMethod, value)
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+// recordPanic records a recovered panic value as an exception event with the
+// stack of the panicking goroutine on the span of ctx, marks the span as
+// failed, logs it and returns the status the client gets. It must be called
+// from the deferred function that recovered.
+func recordPanic(ctx context.Context, logger *logrus.Logger, fullMethod string, value any) error {
+	const op = "interceptor.Recover"
+
+	message := fmt.Sprint(value)
+	stack := string(debug.Stack())
+
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent(semconv.ExceptionEventName, trace.WithAttributes(
+		semconv.ExceptionType(fmt.Sprintf("%T", value)),
+		semconv.ExceptionMessage(message),
+		semconv.ExceptionStacktrace(stack),
+	))
+	span.SetStatus(otelcodes.Error, "panic: "+message)
+
+	logging.LogWithTrace(ctx, logger).WithFields(logrus.Fields{
+		"[op]":        op,
+		"full_method": fullMethod,
+		"panic":       message,
+		"stack":       stack,
+	}).Error("recovered from panic")
+
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"service-b/store"
+	"service-b/util/logging"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type ChatParams struct {
+	Message string `json:"message"`
+}
+
+type ChatResult struct {
+	PongMessage string `json:"pong_message"`
+}
+
+// Chat answers a single message of a chat session
+func (service *Service) Chat(ctx context.Context, params *ChatParams) (*ChatResult, error) {
+	const op = "service.Service.Chat"
+
+	// Start span
+	ctx, span := service.tracer.Start(ctx, op)
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("service.operation", "chat"),
+		attribute.String("service.input.params", fmt.Sprintf("%+v", params)),
+	)
+
+	// Get logger with trace id
+	logger := logging.LogWithTrace(ctx, service.logger)
+
+	logger.WithFields(logrus.Fields{
+		"[op]":   op,
+		"params": params,
+	}).Info()
+
+	arg := &store.ChatArgs{
+		Message: params.Message,
+	}
+
+	data, err := service.store.Chat(ctx, arg)
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"[op]":   op,
+			"params": params,
+			"error":  err,
+		}).Error()
+
+		// Record the error in the span
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, err
+	}
+
+	result := &ChatResult{
+		PongMessage: data.PongMessage,
+	}
+
+	// Record success attributes
+	span.SetAttributes(
+		attribute.String("service.output.result", fmt.Sprintf("%+v", result)),
+	)
+	span.SetStatus(codes.Ok, "service operation completed successfully")
+
+	return result, nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"service-b/store"
 	"service-b/util/logging"
+	"service-b/util/wait"
 
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel/attribute"
@@ -42,7 +43,20 @@ func (service *Service) Ping(ctx context.Context, params *PingParams) (*PingResu
 	}).Info()
 
 	// Simulate a service operation
-	time.Sleep(500 * time.Millisecond)
+	err := wait.Sleep(ctx, 500*time.Millisecond)
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"[op]":   op,
+			"params": params,
+			"error":  err,
+		}).Error()
+
+		// Record the error in the span
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, err
+	}
 
 	arg := &store.PingArgs{
 		PingMessage: params.PingMessage,
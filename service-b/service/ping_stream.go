@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"service-b/store"
+	"service-b/util/logging"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type PingStreamParams struct {
+	PingMessage string        `json:"ping_message"`
+	Count       int           `json:"count"`
+	Interval    time.Duration `json:"interval"`
+}
+
+type PingStreamResult struct {
+	PongMessage string `json:"pong_message"`
+	Sequence    int    `json:"sequence"`
+}
+
+// PingStream streams the pong messages produced by the store to emit
+func (service *Service) PingStream(ctx context.Context, params *PingStreamParams, emit func(*PingStreamResult) error) error {
+	const op = "service.Service.PingStream"
+
+	// Start span
+	ctx, span := service.tracer.Start(ctx, op)
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("service.operation", "ping_stream"),
+		attribute.String("service.input.params", fmt.Sprintf("%+v", params)),
+	)
+
+	// Get logger with trace id
+	logger := logging.LogWithTrace(ctx, service.logger)
+
+	logger.WithFields(logrus.Fields{
+		"[op]":   op,
+		"params": params,
+	}).Info()
+
+	arg := &store.PingStreamArgs{
+		PingMessage: params.PingMessage,
+		Count:       params.Count,
+		Interval:    params.Interval,
+	}
+
+	err := service.store.PingStream(ctx, arg, func(data *store.PingStreamData) error {
+		return emit(&PingStreamResult{
+			PongMessage: data.PongMessage,
+			Sequence:    data.Sequence,
+		})
+	})
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"[op]":   op,
+			"params": params,
+			"error":  err,
+		}).Error()
+
+		// Record the error in the span
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "service operation completed successfully")
+
+	return nil
+}
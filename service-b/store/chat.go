@@ -0,0 +1,82 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"service-b/util/logging"
+	"service-b/util/wait"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type ChatArgs struct {
+	Message string `json:"message"`
+}
+
+type ChatData struct {
+	PongMessage string `json:"message"`
+}
+
+func (store *Store) Chat(ctx context.Context, args *ChatArgs) (*ChatData, error) {
+	const op = "store.Store.Chat"
+
+	// Start span
+	ctx, span := store.tracer.Start(ctx, op)
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("store.operation", "chat"),
+		attribute.String("store.input.args", fmt.Sprintf("%+v", args)),
+	)
+
+	// Get logger with trace id
+	logger := logging.LogWithTrace(ctx, store.logger)
+
+	logger.WithFields(logrus.Fields{
+		"[op]": op,
+		"args": args,
+	}).Info()
+
+	if strings.Contains(args.Message, "error") {
+		err := fmt.Errorf("error in store.chat")
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, err
+	}
+
+	// Simulate a database operation
+	span.AddEvent("database_query_start")
+	err := wait.Sleep(ctx, 100*time.Millisecond)
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"[op]":  op,
+			"args":  args,
+			"error": err,
+		}).Error()
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, err
+	}
+	span.AddEvent("database_query_end")
+
+	data := &ChatData{
+		PongMessage: fmt.Sprintf("pong %s", args.Message),
+	}
+
+	// Record success attributes
+	span.SetAttributes(
+		attribute.String("store.output.data", fmt.Sprintf("%+v", data)),
+	)
+	span.SetStatus(codes.Ok, "store operation completed successfully")
+
+	return data, nil
+}
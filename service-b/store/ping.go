@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"service-b/util/logging"
+	"service-b/util/wait"
 
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel/attribute"
@@ -49,7 +50,19 @@ func (store *Store) Ping(ctx context.Context, args *PingArgs) (*PingData, error)
 
 	// Simulate a database operation
 	span.AddEvent("database_query_start")
-	time.Sleep(500 * time.Millisecond)
+	err := wait.Sleep(ctx, 500*time.Millisecond)
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"[op]":  op,
+			"args":  args,
+			"error": err,
+		}).Error()
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, err
+	}
 	span.AddEvent("database_query_end")
 
 	data := &PingData{
@@ -0,0 +1,96 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"service-b/util/logging"
+	"service-b/util/wait"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type PingStreamArgs struct {
+	PingMessage string        `json:"message"`
+	Count       int           `json:"count"`
+	Interval    time.Duration `json:"interval"`
+}
+
+type PingStreamData struct {
+	PongMessage string `json:"message"`
+	Sequence    int    `json:"sequence"`
+}
+
+// PingStream produces args.Count pong messages, args.Interval apart, and hands
+// each of them to emit. It stops early when ctx is done or emit fails.
+func (store *Store) PingStream(ctx context.Context, args *PingStreamArgs, emit func(*PingStreamData) error) error {
+	const op = "store.Store.PingStream"
+
+	// Start span
+	ctx, span := store.tracer.Start(ctx, op)
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("store.operation", "ping_stream"),
+		attribute.String("store.input.args", fmt.Sprintf("%+v", args)),
+	)
+
+	// Get logger with trace id
+	logger := logging.LogWithTrace(ctx, store.logger)
+
+	logger.WithFields(logrus.Fields{
+		"[op]": op,
+		"args": args,
+	}).Info()
+
+	if strings.Contains(args.PingMessage, "error") {
+		err := fmt.Errorf("error in store.ping_stream")
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return err
+	}
+
+	for sequence := 1; sequence <= args.Count; sequence++ {
+		if sequence > 1 {
+			// Wait for the next message unless the caller went away
+			err := wait.Sleep(ctx, args.Interval)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+
+				return err
+			}
+		}
+
+		data := &PingStreamData{
+			PongMessage: fmt.Sprintf("pong %s", args.PingMessage),
+			Sequence:    sequence,
+		}
+
+		span.AddEvent("pong_generated", trace.WithAttributes(
+			attribute.Int("store.stream.sequence", sequence),
+		))
+
+		err := emit(data)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+
+			return err
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("store.output.count", args.Count),
+	)
+	span.SetStatus(codes.Ok, "store operation completed successfully")
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"service-b/util/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrNoCredentials is returned when a call carries no bearer token
+var ErrNoCredentials = errors.New("missing credentials")
+
+// Verifier checks the tokens service-a issues to assert the principal of the
+// calls it makes
+type Verifier struct {
+	parser *jwt.Parser
+	secret []byte
+}
+
+// NewVerifier creates a verifier for the auth config
+func NewVerifier(config config.Auth) *Verifier {
+	return &Verifier{
+		parser: jwt.NewParser(
+			jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+			jwt.WithIssuer(config.Issuer),
+			jwt.WithAudience(config.Audience),
+			jwt.WithLeeway(config.Leeway),
+			jwt.WithExpirationRequired(),
+		),
+		secret: []byte(config.Secret),
+	}
+}
+
+// Enabled reports whether a secret to verify tokens with is configured
+func (verifier *Verifier) Enabled() bool {
+	return len(verifier.secret) > 0
+}
+
+// Verify returns the principal asserted by the value of an authorization
+// metadata entry
+func (verifier *Verifier) Verify(authorization string) (Principal, error) {
+	if authorization == "" {
+		return Principal{}, ErrNoCredentials
+	}
+
+	tokenString, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok {
+		return Principal{}, errors.New("authorization must hold a bearer token")
+	}
+
+	claims := &forwardedClaims{}
+
+	_, err := verifier.parser.ParseWithClaims(strings.TrimSpace(tokenString), claims, func(*jwt.Token) (any, error) {
+		return verifier.secret, nil
+	})
+	if err != nil {
+		return Principal{}, fmt.Errorf("invalid bearer token: %w", err)
+	}
+
+	if claims.Subject == "" {
+		return Principal{}, errors.New("invalid bearer token: missing sub claim")
+	}
+
+	principal := Principal{Subject: claims.Subject}
+	if len(claims.AMR) > 0 {
+		principal.Method = claims.AMR[0]
+	}
+
+	return principal, nil
+}
+
+// forwardedClaims adds how the principal authenticated (RFC 8176) to the
+// registered claims
+type forwardedClaims struct {
+	jwt.RegisteredClaims
+
+	AMR []string `json:"amr,omitempty"`
+}
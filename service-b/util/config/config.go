@@ -2,6 +2,8 @@ package config
 
 import (
 	"fmt"
+	"path/filepath"
+	"reflect"
 
 	"github.com/spf13/viper"
 )
@@ -10,26 +12,115 @@ import (
 type Config struct {
 	App        App        `mapstructure:"app"`
 	OtelTracer OtelTracer `mapstructure:"otel_tracer"`
+	Logging    Logging    `mapstructure:"logging"`
+	Auth       Auth       `mapstructure:"auth"`
 }
 
 // LoadConfig reads configuration from file or environment variables.
-func LoadConfig(path string) (config Config, err error) {
-	viper.AddConfigPath(path)
-	viper.SetConfigName("config")
-	viper.SetConfigType("json")
+//
+// file is an explicit config file in json, yaml or toml format; when empty,
+// config.{json,yaml,yml,toml} is looked up in the working directory and may
+// be absent. When env is set, the overlay next to the base file (e.g.
+// config.production.yaml for env "production") is deep merged on top of it.
+func LoadConfig(file, env string) (config Config, err error) {
+	config, _, err = load(file, env)
 
-	// Enable automatic environment variable reading
-	viper.AutomaticEnv()
+	return
+}
+
+// load reads the configuration and records where the value of each key came from
+func load(file, env string) (config Config, sources map[string]string, err error) {
+	v := viper.New()
+	t := reflect.TypeOf(config)
+	sources = make(map[string]string)
+
+	// Register the defaults declared on the config model
+	setDefaults(v, t)
+
+	// Enable environment variable overrides for every key
+	bindEnvs(v, t)
+
+	// The config file is optional when everything comes from the environment
+	if file == "" {
+		file = findFile(".", "config")
+	}
+
+	if file != "" {
+		v.SetConfigFile(file)
+
+		err = v.ReadInConfig()
+		if err != nil {
+			return config, sources, fmt.Errorf("failed to read configuration file: %s", err)
+		}
+
+		recordSources(v, t, sources, file)
+	}
+
+	if env != "" {
+		base := file
+		if base == "" {
+			base = filepath.Join(".", "config")
+		}
+
+		overlay := overlayFile(base, env)
+		if overlay == "" {
+			return config, sources, fmt.Errorf("failed to find configuration overlay for environment %q next to %s", env, base)
+		}
+
+		ov := viper.New()
+		ov.SetConfigFile(overlay)
+
+		err = ov.ReadInConfig()
+		if err != nil {
+			return config, sources, fmt.Errorf("failed to read configuration overlay: %s", err)
+		}
 
-	err = viper.ReadInConfig()
+		err = v.MergeConfigMap(ov.AllSettings())
+		if err != nil {
+			return config, sources, fmt.Errorf("failed to merge configuration overlay: %s", err)
+		}
+
+		recordSources(ov, t, sources, overlay)
+	}
+
+	recordEnvSources(t, sources)
+
+	// Maps are read from the environment as JSON objects
+	err = readJSONEnvs(v, t)
 	if err != nil {
-		return config, fmt.Errorf("failed to read configuration file: %s", err)
+		return config, sources, err
 	}
 
-	err = viper.Unmarshal(&config)
+	// Secrets may be kept in files referenced by *_FILE environment variables
+	err = readSecretFiles(v, t, sources)
 	if err != nil {
-		return config, fmt.Errorf("failed to unmarshal configuration: %s", err)
+		return config, sources, err
+	}
+
+	err = v.Unmarshal(&config)
+	if err != nil {
+		return config, sources, fmt.Errorf("failed to unmarshal configuration: %s", err)
+	}
+
+	// ... or in files referenced as file://<path> values
+	err = resolveSecretRefs(&config, sources)
+	if err != nil {
+		return config, sources, err
+	}
+
+	err = config.Validate()
+	if err != nil {
+		return config, sources, fmt.Errorf("invalid configuration:\n%w", err)
 	}
 
 	return
 }
+
+// recordSources marks the keys set in the config file read by v as coming from file
+func recordSources(v *viper.Viper, t reflect.Type, sources map[string]string, file string) {
+	walkFields(t, "", func(key string, _ reflect.StructField) {
+		if v.InConfig(key) {
+			sources[key] = file
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// envPrefix namespaces the environment variables read by this service,
+// e.g. otel_tracer.endpoint is read from SERVICE_B_OTEL_TRACER_ENDPOINT
+const envPrefix = "SERVICE_B"
+
+var envKeyReplacer = strings.NewReplacer(".", "_")
+
+// bindEnvs maps every key of the given struct to its environment variable.
+//
+// viper.AutomaticEnv only resolves keys viper already knows about, so keys
+// that are absent from the config file must be bound explicitly for
+// viper.Unmarshal to pick them up.
+func bindEnvs(v *viper.Viper, t reflect.Type) {
+	v.SetEnvPrefix(envPrefix)
+	v.SetEnvKeyReplacer(envKeyReplacer)
+	v.AutomaticEnv()
+
+	walkFields(t, "", func(key string, _ reflect.StructField) {
+		_ = v.BindEnv(key)
+	})
+}
+
+// envName returns the environment variable read for the given key
+func envName(key string) string {
+	return envPrefix + "_" + strings.ToUpper(envKeyReplacer.Replace(key))
+}
+
+// recordEnvSources marks the keys overridden from the environment
+func recordEnvSources(t reflect.Type, sources map[string]string) {
+	walkFields(t, "", func(key string, _ reflect.StructField) {
+		if _, ok := os.LookupEnv(envName(key)); ok {
+			sources[key] = "env " + envName(key)
+		}
+	})
+}
+
+// readJSONEnvs sets every map key whose environment variable is set to the
+// JSON object it holds, e.g. SERVICE_B_<KEY>='{"name": "value"}'.
+//
+// viper reads environment variables as plain strings, which cannot be
+// decoded into a map.
+func readJSONEnvs(v *viper.Viper, t reflect.Type) error {
+	var errs []string
+
+	walkFields(t, "", func(key string, field reflect.StructField) {
+		if field.Type.Kind() != reflect.Map {
+			return
+		}
+
+		raw, ok := os.LookupEnv(envName(key))
+		if !ok {
+			return
+		}
+
+		var value map[string]any
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: must hold a JSON object: %s", envName(key), err))
+			return
+		}
+
+		v.Set(key, value)
+	})
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to read environment: %s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
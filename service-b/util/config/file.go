@@ -0,0 +1,31 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// extensions lists the supported config file formats in lookup order
+var extensions = []string{"json", "yaml", "yml", "toml"}
+
+// findFile returns the first "<dir>/<stem>.<ext>" that exists, or "" if none does
+func findFile(dir, stem string) string {
+	for _, ext := range extensions {
+		file := filepath.Join(dir, stem+"."+ext)
+		if _, err := os.Stat(file); err == nil {
+			return file
+		}
+	}
+
+	return ""
+}
+
+// overlayFile returns the environment overlay of a base config file,
+// e.g. config.production.yaml for config.yaml and env "production".
+// Any supported format is accepted for the overlay.
+func overlayFile(base, env string) string {
+	stem := strings.TrimSuffix(filepath.Base(base), filepath.Ext(base))
+
+	return findFile(filepath.Dir(base), stem+"."+env)
+}
@@ -1,18 +1,47 @@
 package config
 
+import "time"
+
 // App config
 
 type App struct {
-	Name               string `mapstructure:"name"`
-	Host               string `mapstructure:"host"` // Bind address (0.0.0.0 for listening)
-	Port               int    `mapstructure:"port"`
-	RegisterAddress    string `mapstructure:"register_address"`     // Address for service registration
-	HealthCheckAddress string `mapstructure:"health_check_address"` // Address for Consul health checks
+	Name               string        `mapstructure:"name" validate:"required"`
+	Host               string        `mapstructure:"host" default:"0.0.0.0"` // Bind address (0.0.0.0 for listening)
+	Port               int           `mapstructure:"port" default:"50051" validate:"port"`
+	RegisterAddress    string        `mapstructure:"register_address"`                                 // Address for service registration
+	HealthCheckAddress string        `mapstructure:"health_check_address"`                             // Address for Consul health checks
+	ShutdownTimeout    time.Duration `mapstructure:"shutdown_timeout" default:"15s" validate:"min=0s"` // Time allowed to drain calls and flush telemetry
 }
 
 // Otel tracer config
 
 type OtelTracer struct {
-	Name     string `mapstructure:"name"`
-	Endpoint string `mapstructure:"endpoint"`
+	Name        string  `mapstructure:"name" validate:"required"`
+	Endpoint    string  `mapstructure:"endpoint" validate:"required,hostport"`
+	SampleRatio float64 `mapstructure:"sample_ratio" default:"1.0" validate:"min=0,max=1" reload:"live"` // Fraction of new traces to sample (0..1)
+	Headers     string  `mapstructure:"headers" secret:"true"`                                           // Exporter headers as "key=value,key2=value2"
+}
+
+// Logging config
+
+type Logging struct {
+	Level    string          `mapstructure:"level" default:"debug" validate:"oneof=trace|debug|info|warn|error" reload:"live"`
+	Sampling LoggingSampling `mapstructure:"sampling"`
+	Redact   []string        `mapstructure:"redact" default:"authorization,password,secret,token" reload:"live"` // Fields whose value is never logged
+}
+
+type LoggingSampling struct {
+	Interval    time.Duration `mapstructure:"interval" default:"1s" validate:"min=0s" reload:"live"` // Sampling window, e.g. "1s"
+	Limit       int           `mapstructure:"limit" default:"10" validate:"min=0" reload:"live"`     // Entries kept per [op]/message per window (0 disables sampling)
+	KeepSampled bool          `mapstructure:"keep_sampled" default:"false" reload:"live"`            // Never sample the lines of sampled traces, all of them at a sample ratio of 1
+}
+
+// Auth config
+
+type Auth struct {
+	Required bool          `mapstructure:"required" default:"false"`               // Reject calls without a valid principal token
+	Secret   string        `mapstructure:"secret" secret:"true"`                   // HS256 secret shared with service-a, empty disables verification
+	Issuer   string        `mapstructure:"issuer" default:"service-a"`             // Required "iss" claim
+	Audience string        `mapstructure:"audience" default:"service-b"`           // Required "aud" claim
+	Leeway   time.Duration `mapstructure:"leeway" default:"30s" validate:"min=0s"` // Clock skew tolerated on "exp" and "nbf"
 }
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Fields tagged `secret:"true"` hold credentials. Their value can be read
+// from a file, either through a <ENV_NAME>_FILE environment variable or by
+// setting the value to file://<path>, and it is masked whenever the config
+// is printed.
+
+const (
+	secretFileSuffix = "_FILE"
+	secretRefPrefix  = "file://"
+	secretMask       = "******"
+)
+
+// readSecretFiles sets every secret key whose <ENV_NAME>_FILE variable is set
+// to the content of that file
+func readSecretFiles(v *viper.Viper, t reflect.Type, sources map[string]string) error {
+	var errs []string
+
+	walkFields(t, "", func(key string, field reflect.StructField) {
+		if !isSecret(field) {
+			return
+		}
+
+		name := envName(key) + secretFileSuffix
+
+		path, ok := os.LookupEnv(name)
+		if !ok {
+			return
+		}
+
+		value, err := readSecret(path)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", name, err))
+			return
+		}
+
+		v.Set(key, value)
+		sources[key] = "env " + name
+	})
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to read secret files: %s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// resolveSecretRefs replaces file://<path> secret values by the content of the file
+func resolveSecretRefs(config *Config, sources map[string]string) error {
+	var errs []string
+
+	value := reflect.ValueOf(config).Elem()
+
+	walkFields(value.Type(), "", func(key string, field reflect.StructField) {
+		target := value.FieldByIndex(field.Index)
+		if !isSecret(field) || target.Kind() != reflect.String {
+			return
+		}
+
+		path, ok := strings.CutPrefix(target.String(), secretRefPrefix)
+		if !ok {
+			return
+		}
+
+		secret, err := readSecret(path)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", key, err))
+			return
+		}
+
+		target.SetString(secret)
+		sources[key] = strings.TrimSpace(sources[key] + " " + secretRefPrefix + path)
+	})
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to read secret files: %s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+func readSecret(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	// Secret files usually end with a newline that is not part of the secret
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+func isSecret(field reflect.StructField) bool {
+	return field.Tag.Get("secret") == "true"
+}
+
+// Masked returns a copy of the config with every secret value masked
+func (config Config) Masked() Config {
+	value := reflect.ValueOf(&config).Elem()
+
+	walkFields(value.Type(), "", func(_ string, field reflect.StructField) {
+		target := value.FieldByIndex(field.Index)
+		if isSecret(field) && target.Kind() == reflect.String && !target.IsZero() {
+			target.SetString(secretMask)
+		}
+	})
+
+	return config
+}
+
+// String formats the config with its secrets masked, so that printing the
+// config with fmt never leaks them
+func (config Config) String() string {
+	type plain Config
+
+	return fmt.Sprintf("%+v", plain(config.Masked()))
+}
+
+// Setting describes the effective value of a single config key
+type Setting struct {
+	Key    string
+	Value  string
+	Source string
+}
+
+// Explain loads the configuration like LoadConfig and describes every key with
+// its masked value and the source it was read from
+func Explain(file, env string) ([]Setting, error) {
+	config, sources, err := load(file, env)
+	if err != nil {
+		return nil, err
+	}
+
+	masked := reflect.ValueOf(config.Masked())
+
+	var settings []Setting
+	walkFields(masked.Type(), "", func(key string, field reflect.StructField) {
+		source, ok := sources[key]
+		if !ok {
+			source = "unset"
+			if _, hasDefault := field.Tag.Lookup("default"); hasDefault {
+				source = "default"
+			}
+		}
+
+		settings = append(settings, Setting{
+			Key:    key,
+			Value:  fmt.Sprintf("%v", masked.FieldByIndex(field.Index).Interface()),
+			Source: source,
+		})
+	})
+
+	return settings, nil
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSecretsAreNeverPrinted(t *testing.T) {
+	dir := t.TempDir()
+
+	secretFile := filepath.Join(dir, "auth-secret")
+	if err := os.WriteFile(secretFile, []byte("secret-from-env-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	headersFile := filepath.Join(dir, "headers")
+	if err := os.WriteFile(headersFile, []byte("api-key=headers-from-ref"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// One secret per source: a *_FILE variable and a file:// reference
+	file := writeConfig(t, `{
+  "app": {"name": "service-b"},
+  "otel_tracer": {"name": "tracer", "endpoint": "localhost:4317", "headers": "file://`+headersFile+`"}
+}`)
+	t.Setenv("SERVICE_B_AUTH_SECRET_FILE", secretFile)
+
+	secrets := []string{"headers-from-ref", "secret-from-env-file"}
+
+	config, err := LoadConfig(file, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The secrets themselves are loaded...
+	if config.OtelTracer.Headers != "api-key=headers-from-ref" || config.Auth.Secret != "secret-from-env-file" {
+		t.Fatalf("secrets not loaded: %q %q", config.OtelTracer.Headers, config.Auth.Secret)
+	}
+
+	// ...but never printed
+	masked := config.Masked()
+	if masked.OtelTracer.Headers != secretMask || masked.Auth.Secret != secretMask {
+		t.Fatalf("secrets not masked: %+v", masked)
+	}
+
+	if config.Auth.Secret != "secret-from-env-file" {
+		t.Fatal("Masked changed the config it was called on")
+	}
+
+	settings, err := Explain(file, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var printed strings.Builder
+	sources := make(map[string]string)
+	for _, setting := range settings {
+		fmt.Fprintf(&printed, "%s %s %s\n", setting.Key, setting.Value, setting.Source)
+		sources[setting.Key] = setting.Source
+	}
+
+	for name, output := range map[string]string{
+		"String()":     config.String(),
+		"%v":           fmt.Sprintf("%v", config),
+		"%+v":          fmt.Sprintf("%+v", config),
+		"config print": printed.String(),
+	} {
+		for _, secret := range secrets {
+			if strings.Contains(output, secret) {
+				t.Errorf("%s prints %q:\n%s", name, secret, output)
+			}
+		}
+	}
+
+	if sources["auth.secret"] != "env SERVICE_B_AUTH_SECRET_FILE" || !strings.HasSuffix(sources["otel_tracer.headers"], "file://"+headersFile) {
+		t.Fatalf("secret sources = %q, %q", sources["auth.secret"], sources["otel_tracer.headers"])
+	}
+}
+
+func TestMissingSecretFile(t *testing.T) {
+	file := writeConfig(t, minimal)
+	t.Setenv("SERVICE_B_AUTH_SECRET_FILE", filepath.Join(t.TempDir(), "missing"))
+
+	if _, err := LoadConfig(file, ""); err == nil || !strings.Contains(err.Error(), "SERVICE_B_AUTH_SECRET_FILE") {
+		t.Fatalf("LoadConfig() = %v, want the missing secret file reported", err)
+	}
+}
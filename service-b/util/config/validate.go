@@ -0,0 +1,188 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Fields declare their defaults and checks with struct tags next to the
+// mapstructure key, e.g.
+//
+//	Port int `mapstructure:"port" default:"4000" validate:"port"`
+//
+// Supported rules (comma separated):
+//
+//	required    value must not be empty
+//	port        integer between 1 and 65535
+//	hostport    "host:port" with a valid port
+//	oneof=a|b   value must be one of the listed values
+//	min=N       number or duration must be >= N
+//	max=N       number or duration must be <= N
+
+// setDefaults registers every `default` tag of the given struct with viper
+func setDefaults(v *viper.Viper, t reflect.Type) {
+	walkFields(t, "", func(key string, field reflect.StructField) {
+		if value, ok := field.Tag.Lookup("default"); ok {
+			v.SetDefault(key, value)
+		}
+	})
+}
+
+// walkFields calls fn with the dotted key of every leaf field of the given
+// struct. The field's Index holds its full path from the root struct.
+func walkFields(t reflect.Type, prefix string, fn func(key string, field reflect.StructField)) {
+	walkFieldsAt(t, prefix, nil, fn)
+}
+
+func walkFieldsAt(t reflect.Type, prefix string, index []int, fn func(key string, field reflect.StructField)) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		field.Index = append(append([]int(nil), index...), i)
+		key := joinKey(prefix, field.Tag.Get("mapstructure"))
+
+		if field.Type.Kind() == reflect.Struct {
+			walkFieldsAt(field.Type, key, field.Index, fn)
+			continue
+		}
+
+		fn(key, field)
+	}
+}
+
+// Validate checks the configuration and returns every violation at once
+func (config Config) Validate() error {
+	return errors.Join(validateStruct(reflect.ValueOf(config), "")...)
+}
+
+func validateStruct(value reflect.Value, prefix string) []error {
+	var errs []error
+
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldValue := value.Field(i)
+		key := joinKey(prefix, field.Tag.Get("mapstructure"))
+
+		if field.Type.Kind() == reflect.Struct {
+			errs = append(errs, validateStruct(fieldValue, key)...)
+			continue
+		}
+
+		rules := field.Tag.Get("validate")
+		if rules == "" {
+			continue
+		}
+
+		for _, rule := range strings.Split(rules, ",") {
+			if err := checkRule(fieldValue, rule); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+			}
+		}
+	}
+
+	return errs
+}
+
+func checkRule(value reflect.Value, rule string) error {
+	name, arg, _ := strings.Cut(rule, "=")
+
+	switch name {
+	case "required":
+		if value.IsZero() {
+			return errors.New("is required")
+		}
+
+	case "port":
+		if port := value.Int(); port < 1 || port > 65535 {
+			return fmt.Errorf("must be a port between 1 and 65535, got %d", port)
+		}
+
+	case "hostport":
+		s := value.String()
+		if s == "" {
+			return nil
+		}
+
+		host, port, err := net.SplitHostPort(s)
+		if err != nil {
+			return fmt.Errorf("must be in host:port form, got %q", s)
+		}
+
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 || host == "" {
+			return fmt.Errorf("must be in host:port form with a valid port, got %q", s)
+		}
+
+	case "oneof":
+		s := value.String()
+		allowed := strings.Split(arg, "|")
+		for _, a := range allowed {
+			if s == a {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("must be one of %s, got %q", strings.Join(allowed, ", "), s)
+
+	case "min", "max":
+		n, limit, err := numbers(value, arg)
+		if err != nil {
+			return err
+		}
+
+		if name == "min" && n < limit {
+			return fmt.Errorf("must be at least %s", arg)
+		}
+
+		if name == "max" && n > limit {
+			return fmt.Errorf("must be at most %s", arg)
+		}
+
+	default:
+		return fmt.Errorf("unknown validation rule %q", rule)
+	}
+
+	return nil
+}
+
+// numbers returns the field value and the rule argument as comparable floats
+func numbers(value reflect.Value, arg string) (float64, float64, error) {
+	if value.Type() == reflect.TypeOf(time.Duration(0)) {
+		limit, err := time.ParseDuration(arg)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid duration limit %q", arg)
+		}
+
+		return float64(value.Int()), float64(limit), nil
+	}
+
+	limit, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid numeric limit %q", arg)
+	}
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), limit, nil
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), limit, nil
+	case reflect.String, reflect.Slice, reflect.Map:
+		return float64(value.Len()), limit, nil
+	}
+
+	return 0, 0, fmt.Errorf("min/max not supported for %s", value.Kind())
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + "." + key
+}
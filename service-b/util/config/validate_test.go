@@ -0,0 +1,101 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// minimal holds the keys without defaults that a valid config needs
+const minimal = `{
+  "app": {"name": "service-b"},
+  "otel_tracer": {"name": "tracer", "endpoint": "localhost:4317"}
+}`
+
+// writeConfig writes content to a config.json of its own and returns its path
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return file
+}
+
+func TestCheckRule(t *testing.T) {
+	tests := []struct {
+		rule    string
+		value   any
+		wantErr bool
+	}{
+		{rule: "required", value: "service-b"},
+		{rule: "required", value: "", wantErr: true},
+		{rule: "port", value: 4000},
+		{rule: "port", value: 0, wantErr: true},
+		{rule: "port", value: 65536, wantErr: true},
+		{rule: "hostport", value: "localhost:4317"},
+		{rule: "hostport", value: ""},
+		{rule: "hostport", value: "localhost", wantErr: true},
+		{rule: "hostport", value: ":4317", wantErr: true},
+		{rule: "hostport", value: "localhost:http", wantErr: true},
+		{rule: "oneof=debug|info", value: "info"},
+		{rule: "oneof=debug|info", value: "loud", wantErr: true},
+		{rule: "min=1", value: 1},
+		{rule: "min=1", value: 0, wantErr: true},
+		{rule: "max=1", value: 0.5},
+		{rule: "max=1", value: 1.5, wantErr: true},
+		{rule: "min=1s", value: time.Second},
+		{rule: "min=1s", value: time.Millisecond, wantErr: true},
+		{rule: "unknown", value: "x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %v", tt.rule, tt.value), func(t *testing.T) {
+			err := checkRule(reflect.ValueOf(tt.value), tt.rule)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkRule(%v, %q) = %v, want error=%v", tt.value, tt.rule, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadConfigAppliesDefaults(t *testing.T) {
+	config, err := LoadConfig(writeConfig(t, minimal), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.App.Port != 50051 || config.Logging.Level != "debug" || config.Auth.Audience != "service-b" || config.App.ShutdownTimeout != 15*time.Second {
+		t.Fatalf("defaults not applied: %+v", config)
+	}
+}
+
+func TestLoadConfigReportsEveryError(t *testing.T) {
+	_, err := LoadConfig(writeConfig(t, `{
+  "app": {"port": 70000, "shutdown_timeout": "-1s"},
+  "otel_tracer": {"name": "tracer", "endpoint": "localhost", "sample_ratio": 2},
+  "logging": {"level": "loud"}
+}`), "")
+	if err == nil {
+		t.Fatal("invalid config accepted")
+	}
+
+	for _, want := range []string{
+		"app.name: is required",
+		"app.port: must be a port",
+		"otel_tracer.endpoint: must be in host:port form",
+		"otel_tracer.sample_ratio: must be at most 1",
+		"logging.level: must be one of",
+		"app.shutdown_timeout: must be at least 0s",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not report %q:\n%v", want, err)
+		}
+	}
+}
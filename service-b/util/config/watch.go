@@ -0,0 +1,295 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"service-b/util/logging"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Fields tagged `reload:"live"` are applied while the service is running.
+// Changes to any other field are only reported as pending until restart.
+
+// reloadDelay debounces the bursts of events editors emit while saving
+const reloadDelay = 200 * time.Millisecond
+
+// Watcher reloads the config file on change and publishes it to subscribers
+type Watcher struct {
+	file string
+	env  string
+
+	logger *logrus.Logger
+	tracer trace.Tracer
+
+	reloadMu sync.Mutex
+
+	mu          sync.RWMutex
+	current     Config
+	pending     []string
+	err         error
+	subscribers map[int]func(Config)
+	nextID      int
+}
+
+// NewWatcher creates a Watcher for the config loaded from file and env
+func NewWatcher(
+	file string,
+	env string,
+	current Config,
+	logger *logrus.Logger,
+	tracer trace.Tracer,
+) *Watcher {
+	if file == "" {
+		file = findFile(".", "config")
+	}
+
+	return &Watcher{
+		file: file,
+		env:  env,
+
+		logger: logger,
+		tracer: tracer,
+
+		current:     current,
+		subscribers: make(map[int]func(Config)),
+	}
+}
+
+// Current returns the config currently in effect
+func (w *Watcher) Current() Config {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.current
+}
+
+// Pending returns the keys changed on disk that only take effect after a restart
+func (w *Watcher) Pending() []string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return append([]string(nil), w.pending...)
+}
+
+// Err returns why the last reload was rejected, nil when it was applied
+func (w *Watcher) Err() error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.err
+}
+
+// Subscribe registers fn to be called with every reloaded config and
+// returns a function that removes the subscription
+func (w *Watcher) Subscribe(fn func(Config)) (unsubscribe func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	id := w.nextID
+	w.nextID++
+	w.subscribers[id] = fn
+
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		delete(w.subscribers, id)
+	}
+}
+
+// Run watches the config files until ctx is done
+func (w *Watcher) Run(ctx context.Context) error {
+	const op = "config.Watcher.Run"
+
+	if w.file == "" {
+		w.logger.WithFields(logrus.Fields{
+			"[op]": op,
+		}).Info("no config file in use, hot reload disabled")
+
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	// Watch the directory rather than the file so that atomic saves
+	// (write to temp file + rename) are picked up as well
+	err = watcher.Add(filepath.Dir(w.file))
+	if err != nil {
+		return fmt.Errorf("failed to watch %s: %w", w.file, err)
+	}
+
+	w.logger.WithFields(logrus.Fields{
+		"[op]": op,
+		"file": w.file,
+		"env":  w.env,
+	}).Info("watching config for changes")
+
+	var timer *time.Timer
+	for {
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			if !w.isConfigFile(event.Name) || event.Op == fsnotify.Chmod {
+				continue
+			}
+
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.AfterFunc(reloadDelay, func() {
+				_ = w.Reload(ctx)
+			})
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+
+			w.logger.WithFields(logrus.Fields{
+				"[op]":  op,
+				"error": err.Error(),
+			}).Warn()
+		}
+	}
+}
+
+// Reload reads the config files again, applies the live settings and
+// publishes the result to subscribers
+func (w *Watcher) Reload(ctx context.Context) error {
+	const op = "config.Watcher.Reload"
+
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	// Reloads are not part of any request, so they get a trace of their own
+	ctx, span := w.tracer.Start(context.WithoutCancel(ctx), "config.reload", trace.WithNewRoot())
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("config.file", w.file),
+		attribute.String("config.env", w.env),
+	)
+
+	logger := logging.LogWithTrace(ctx, w.logger)
+
+	loaded, err := LoadConfig(w.file, w.env)
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"[op]":  op,
+			"error": err.Error(),
+		}).Error("config reload rejected, keeping current config")
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		w.mu.Lock()
+		w.err = err
+		w.mu.Unlock()
+
+		return err
+	}
+
+	w.mu.Lock()
+	previous := w.current
+	next, changed, pending := merge(previous, loaded)
+	w.current = next
+	w.pending = pending
+	w.err = nil
+
+	subscribers := make([]func(Config), 0, len(w.subscribers))
+	for _, fn := range w.subscribers {
+		subscribers = append(subscribers, fn)
+	}
+	w.mu.Unlock()
+
+	for _, key := range changed {
+		span.AddEvent("config.changed", trace.WithAttributes(attribute.String("config.key", key)))
+	}
+
+	for _, key := range pending {
+		span.AddEvent("config.pending_restart", trace.WithAttributes(attribute.String("config.key", key)))
+	}
+
+	span.SetAttributes(
+		attribute.StringSlice("config.changed", changed),
+		attribute.StringSlice("config.pending_restart", pending),
+	)
+
+	logger.WithFields(logrus.Fields{
+		"[op]":            op,
+		"changed":         changed,
+		"pending_restart": pending,
+	}).Info("config reloaded")
+
+	if len(changed) > 0 {
+		for _, fn := range subscribers {
+			fn(next)
+		}
+	}
+
+	span.SetStatus(codes.Ok, "config reloaded successfully")
+
+	return nil
+}
+
+func (w *Watcher) isConfigFile(name string) bool {
+	name = filepath.Clean(name)
+	if name == filepath.Clean(w.file) {
+		return true
+	}
+
+	return w.env != "" && name == filepath.Clean(overlayFile(w.file, w.env))
+}
+
+// merge applies the live fields of loaded on top of current. It returns the
+// resulting config, the live keys that changed and the keys whose change is
+// pending a restart.
+func merge(current, loaded Config) (next Config, changed []string, pending []string) {
+	next = current
+
+	nextValue := reflect.ValueOf(&next).Elem()
+	loadedValue := reflect.ValueOf(loaded)
+
+	walkFields(nextValue.Type(), "", func(key string, field reflect.StructField) {
+		target := nextValue.FieldByIndex(field.Index)
+		source := loadedValue.FieldByIndex(field.Index)
+
+		if reflect.DeepEqual(target.Interface(), source.Interface()) {
+			return
+		}
+
+		if field.Tag.Get("reload") != "live" {
+			pending = append(pending, key)
+			return
+		}
+
+		target.Set(source)
+		changed = append(changed, key)
+	})
+
+	return next, changed, pending
+}
@@ -0,0 +1,78 @@
+package listener
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	unixScheme = "unix://"
+
+	// listenFdsStart is the first file descriptor passed by systemd
+	listenFdsStart = 3
+)
+
+// Listen creates the listener the server accepts connections on.
+//
+// A listener inherited through systemd socket activation (LISTEN_FDS) takes
+// precedence. Otherwise host is either a "unix:///path/to.sock" Unix domain
+// socket or a bind address combined with port, e.g. 0.0.0.0 or 127.0.0.1.
+func Listen(host string, port int) (net.Listener, error) {
+	ln, err := inherited()
+	if err != nil || ln != nil {
+		return ln, err
+	}
+
+	if path, ok := strings.CutPrefix(host, unixScheme); ok {
+		return listenUnix(path)
+	}
+
+	return net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+}
+
+// inherited returns the first socket passed by systemd, or nil if there is none
+func inherited() (net.Listener, error) {
+	fds, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || fds < 1 {
+		return nil, nil
+	}
+
+	// The sockets are meant for the process systemd started, not its children
+	if pid := os.Getenv("LISTEN_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return nil, nil
+	}
+
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	file := os.NewFile(listenFdsStart, "LISTEN_FD_3")
+	defer file.Close()
+
+	ln, err := net.FileListener(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to use socket passed by systemd: %w", err)
+	}
+
+	return ln, nil
+}
+
+// listenUnix listens on a Unix domain socket, replacing a stale socket file
+// left behind by a previous run
+func listenUnix(path string) (net.Listener, error) {
+	info, err := os.Stat(path)
+	if err == nil && info.Mode()&fs.ModeSocket != 0 {
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket %s: %w", path, err)
+		}
+	} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	return net.Listen("unix", path)
+}
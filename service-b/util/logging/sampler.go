@@ -0,0 +1,274 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SamplerConfig controls how many repeated log entries are kept per interval
+type SamplerConfig struct {
+	Interval    time.Duration // Length of a sampling window
+	Limit       int           // Entries kept per key within a window
+	KeepSampled bool          // Whether entries of sampled traces bypass sampling
+}
+
+// SamplingFormatter wraps a logrus.Formatter and drops repeated entries.
+//
+// Entries are grouped by level, "[op]" field and message. Within each interval
+// only the first Limit entries of a group are written; the rest are counted and
+// reported on the first entry of the group written after the window rolls over,
+// or by Run once the window is over when no such entry comes. Entries at warn
+// level or above are always written, and so are entries whose trace is sampled
+// when KeepSampled is set.
+type SamplingFormatter struct {
+	formatter logrus.Formatter
+	config    SamplerConfig
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+const maxBuckets = 1024
+
+// summaryMessage is the message of the entries reporting suppressed ones
+const summaryMessage = "log sampling suppressed repeated entries"
+
+type bucket struct {
+	start      time.Time
+	count      int
+	suppressed int
+
+	// What a summary written by Run needs to know of the group
+	logger  *logrus.Logger
+	level   logrus.Level
+	op      any
+	message string
+}
+
+// NewSamplingFormatter creates a SamplingFormatter around the given formatter
+func NewSamplingFormatter(formatter logrus.Formatter, config SamplerConfig) *SamplingFormatter {
+	return &SamplingFormatter{
+		formatter: formatter,
+		config:    config,
+
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// SetConfig replaces the sampling settings
+func (f *SamplingFormatter) SetConfig(config SamplerConfig) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.config = config
+}
+
+// Format implements logrus.Formatter
+func (f *SamplingFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	f.mu.Lock()
+	config := f.config
+	f.mu.Unlock()
+
+	if config.Limit <= 0 || config.Interval <= 0 || alwaysKeep(entry, config.KeepSampled) {
+		return f.formatter.Format(entry)
+	}
+
+	key := fmt.Sprintf("%s|%v|%s", entry.Level, entry.Data["[op]"], entry.Message)
+	now := entry.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	f.mu.Lock()
+	b, ok := f.buckets[key]
+
+	var evicted []*bucket
+	if !ok {
+		evicted = f.prune(now)
+
+		b = &bucket{start: now, level: entry.Level, op: entry.Data["[op]"], message: entry.Message}
+		f.buckets[key] = b
+	}
+
+	suppressed := 0
+	if now.Sub(b.start) >= config.Interval {
+		suppressed = b.suppressed
+		b.start = now
+		b.count = 0
+		b.suppressed = 0
+	}
+
+	if b.count >= config.Limit {
+		b.suppressed++
+		b.logger = entry.Logger
+		f.mu.Unlock()
+
+		return nil, nil
+	}
+	b.count++
+	f.mu.Unlock()
+
+	if suppressed == 0 && len(evicted) == 0 {
+		return f.formatter.Format(entry)
+	}
+
+	// Report the entries dropped by evicted groups and in the previous window
+	// before the current one. The logger is locked while formatting, the
+	// summaries cannot be logged on their own.
+	var out []byte
+	for _, e := range evicted {
+		head, err := f.summary(entry.Logger, e.level, e.op, e.message, e.suppressed, now)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, head...)
+	}
+
+	if suppressed > 0 {
+		head, err := f.summary(entry.Logger, entry.Level, b.op, b.message, suppressed, now)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, head...)
+	}
+
+	tail, err := f.formatter.Format(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(out, tail...), nil
+}
+
+// summary formats the entry reporting suppressed entries of a group
+func (f *SamplingFormatter) summary(logger *logrus.Logger, level logrus.Level, op any, message string, suppressed int, now time.Time) ([]byte, error) {
+	summary := logrus.NewEntry(logger).WithFields(summaryFields(op, message, suppressed))
+	summary.Time = now
+	summary.Level = level
+	summary.Message = summaryMessage
+
+	head, err := f.formatter.Format(summary)
+	if err != nil {
+		return nil, err
+	}
+
+	// The formatter may reuse its buffer
+	return append([]byte{}, head...), nil
+}
+
+// Run reports the entries suppressed in windows that are over, when no entry
+// of their group came since to carry the report, until ctx is done
+func (f *SamplingFormatter) Run(ctx context.Context) {
+	for {
+		f.mu.Lock()
+		interval := f.config.Interval
+		f.mu.Unlock()
+
+		if interval <= 0 {
+			interval = time.Second
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+			f.report(false)
+		}
+	}
+}
+
+// Flush reports every suppressed entry now, e.g. before the process exits
+func (f *SamplingFormatter) Flush() {
+	f.report(true)
+}
+
+// report writes a summary for the groups with suppressed entries, only those
+// whose window is over unless all is set
+func (f *SamplingFormatter) report(all bool) {
+	type summary struct {
+		logger *logrus.Logger
+		level  logrus.Level
+		fields logrus.Fields
+	}
+
+	now := time.Now()
+
+	f.mu.Lock()
+	var summaries []summary
+	for _, b := range f.buckets {
+		if b.suppressed == 0 || b.logger == nil || (!all && now.Sub(b.start) < f.config.Interval) {
+			continue
+		}
+
+		summaries = append(summaries, summary{
+			logger: b.logger,
+			level:  b.level,
+			fields: summaryFields(b.op, b.message, b.suppressed),
+		})
+
+		// A new window starts with nothing left to report
+		b.start = now
+		b.count = 0
+		b.suppressed = 0
+	}
+	f.mu.Unlock()
+
+	// Written through the logger, outside the lock taken again by Format
+	for _, s := range summaries {
+		s.logger.WithFields(s.fields).Log(s.level, summaryMessage)
+	}
+}
+
+func summaryFields(op any, message string, suppressed int) logrus.Fields {
+	return logrus.Fields{
+		"[op]":       op,
+		"message":    message,
+		"suppressed": suppressed,
+	}
+}
+
+// prune drops the buckets whose window is over once the map grows large, and
+// returns those with suppressed entries left to report
+func (f *SamplingFormatter) prune(now time.Time) []*bucket {
+	if len(f.buckets) < maxBuckets {
+		return nil
+	}
+
+	var evicted []*bucket
+	for key, b := range f.buckets {
+		if now.Sub(b.start) < f.config.Interval {
+			continue
+		}
+
+		delete(f.buckets, key)
+		if b.suppressed > 0 {
+			evicted = append(evicted, b)
+		}
+	}
+
+	return evicted
+}
+
+// alwaysKeep reports whether the entry bypasses sampling, keepSampled
+// letting the entries of sampled traces through
+func alwaysKeep(entry *logrus.Entry, keepSampled bool) bool {
+	if entry.Level <= logrus.WarnLevel {
+		return true
+	}
+
+	// Summaries are written once per window already
+	if _, ok := entry.Data["suppressed"]; ok && entry.Message == summaryMessage {
+		return true
+	}
+
+	if !keepSampled || entry.Context == nil {
+		return false
+	}
+
+	return trace.SpanContextFromContext(entry.Context).IsSampled()
+}
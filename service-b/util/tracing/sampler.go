@@ -0,0 +1,37 @@
+package tracing
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// RatioSampler samples a fraction of new traces and follows the parent's
+// decision otherwise. The ratio can be changed while the service is running.
+type RatioSampler struct {
+	sampler atomic.Value // sdktrace.Sampler
+}
+
+// NewRatioSampler creates a RatioSampler with the given ratio
+func NewRatioSampler(ratio float64) *RatioSampler {
+	s := &RatioSampler{}
+	s.SetRatio(ratio)
+
+	return s
+}
+
+// SetRatio replaces the fraction of new traces that are sampled
+func (s *RatioSampler) SetRatio(ratio float64) {
+	s.sampler.Store(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio)))
+}
+
+// ShouldSample implements sdktrace.Sampler
+func (s *RatioSampler) ShouldSample(parameters sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return s.sampler.Load().(sdktrace.Sampler).ShouldSample(parameters)
+}
+
+// Description implements sdktrace.Sampler
+func (s *RatioSampler) Description() string {
+	return fmt.Sprintf("RatioSampler{%s}", s.sampler.Load().(sdktrace.Sampler).Description())
+}